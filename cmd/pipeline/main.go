@@ -4,6 +4,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io/fs"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -14,10 +16,20 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/nguyentantai21042004/caption-flow/internal/api"
+	"github.com/nguyentantai21042004/caption-flow/internal/cache"
+	"github.com/nguyentantai21042004/caption-flow/internal/chapters"
 	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/glossary"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/llm"
 	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
 	"github.com/nguyentantai21042004/caption-flow/internal/processor"
+	"github.com/nguyentantai21042004/caption-flow/internal/progress"
 	"github.com/nguyentantai21042004/caption-flow/internal/summarizer"
+	"github.com/nguyentantai21042004/caption-flow/internal/transcriber"
+	"github.com/nguyentantai21042004/caption-flow/internal/translator"
 	"github.com/nguyentantai21042004/caption-flow/internal/watcher"
 	"github.com/nguyentantai21042004/caption-flow/pkg/executor"
 )
@@ -27,7 +39,10 @@ func main() {
 	target := flag.String("target", "", "Target video file(s) to process (comma-separated or single file)")
 	targetAll := flag.Bool("target-all", false, "Process all video files in input folder")
 	watchMode := flag.Bool("watch", false, "Run in watch mode (monitor input folder)")
-	summarizeMode := flag.Bool("summarize", false, "Summarize all SRT files in output folder via Gemini")
+	summarizeMode := flag.Bool("summarize", false, "Summarize all SRT files in output folder via the configured LLM")
+	serveMode := flag.Bool("serve", false, "Run the HTTP job API (submit, list, cancel, download)")
+	dryRun := flag.Bool("dry-run", false, "Print the commands and paths -target/-target-all would use, without running anything")
+	style := flag.String("style", "", "Summary prompt style for -summarize (a template in gemini.prompts/styles, default gemini.style)")
 	flag.Parse()
 
 	ctx := context.Background()
@@ -40,13 +55,23 @@ func main() {
 	}
 
 	// Initialize logger
-	log := logger.New(cfg.Logging.Level)
+	log := logger.NewWithFormat(cfg.Logging.Level, cfg.Logging.Format)
 	log.Info(ctx, "========================================")
 	log.Info(ctx, "Video Processing Pipeline (M4 Pro Optimized)")
 	log.Info(ctx, "========================================")
 	log.Info(ctx, "System: %s/%s", runtime.GOOS, runtime.GOARCH)
 	log.Info(ctx, "CPU Cores: %d", runtime.NumCPU())
 
+	// Dry run resolves targets and plans without creating folders or job records
+	if *dryRun {
+		target := *target
+		if target == "" {
+			target = strings.Join(discoverVideoFiles(ctx, cfg, log), ",")
+		}
+		runDryRun(ctx, cfg, log, target)
+		return
+	}
+
 	// Verify required directories exist
 	if err := ensureDirectories(cfg); err != nil {
 		log.Error(ctx, "Failed to create directories: %v", err)
@@ -54,12 +79,36 @@ func main() {
 	}
 
 	// Initialize dependencies
-	exec := executor.New()
-	proc := processor.New(cfg, exec, log)
+	m := setupMetrics(ctx, cfg, log)
+	exec := executor.NewWithOptions(executor.Options{
+		Timeout:    cfg.Executor.Timeout,
+		KillGrace:  cfg.Executor.KillGrace,
+		Nice:       cfg.Executor.Nice,
+		MaxCPUTime: cfg.Executor.MaxCPUTime,
+		MaxMemory:  cfg.Executor.MaxMemoryMB * 1024 * 1024,
+	})
+	store, err := jobstore.New(cfg.Paths.Jobs)
+	if err != nil {
+		log.Error(ctx, "Failed to open job store: %v", err)
+		os.Exit(1)
+	}
+	trans, err := transcriber.New(cfg.Whisper, exec, log)
+	if err != nil {
+		log.Error(ctx, "Failed to create transcriber: %v", err)
+		os.Exit(1)
+	}
+	tracker := progress.New()
+	gl := loadGlossary(ctx, cfg, log)
+	// One client, so translation, chapters and summaries share a single key rotation
+	client := setupLLM(ctx, cfg, *summarizeMode, m, log)
+	proc := processor.New(cfg, exec, trans, setupTranslator(ctx, cfg, client, log), setupChapters(ctx, cfg, client, log), setupCache(ctx, cfg, log), gl, store, tracker, m, log)
 
 	// Determine mode
 	if *summarizeMode {
-		runSummarize(ctx, cfg, log)
+		if *style != "" {
+			cfg.Gemini.Style = *style
+		}
+		runSummarize(ctx, cfg, client, gl, m, log)
 		return
 	}
 
@@ -73,17 +122,17 @@ func main() {
 	} else if *target != "" {
 		runTargetMode(ctx, cfg, proc, log, *target)
 	} else if *watchMode {
-		runWatchMode(ctx, cfg, proc, log)
+		runWatchMode(ctx, cfg, proc, store, m, log)
+	} else if *serveMode {
+		runServeMode(ctx, cfg, proc, store, tracker, log)
 	} else {
 		showUsage(ctx, cfg, log)
 	}
 }
 
-// runTargetMode processes target files concurrently using goroutines
-func runTargetMode(ctx context.Context, cfg *config.Config, proc processor.Processor, log logger.Logger, target string) {
-	startTime := time.Now()
-
-	// Parse and validate targets
+// resolveTargets turns a comma-separated list of paths relative to the input folder
+// into existing video paths, skipping missing files
+func resolveTargets(ctx context.Context, cfg *config.Config, log logger.Logger, target string) []string {
 	var validPaths []string
 	for _, t := range strings.Split(target, ",") {
 		t = strings.TrimSpace(t)
@@ -97,7 +146,57 @@ func runTargetMode(ctx context.Context, cfg *config.Config, proc processor.Proce
 		}
 		validPaths = append(validPaths, videoPath)
 	}
+	return validPaths
+}
+
+// runDryRun prints, per target, the commands Process would run and the files it would write or move
+func runDryRun(ctx context.Context, cfg *config.Config, log logger.Logger, target string) {
+	validPaths := resolveTargets(ctx, cfg, log, target)
+	if len(validPaths) == 0 {
+		log.Error(ctx, "No valid files to plan")
+		return
+	}
+
+	planner, err := processor.NewPlanner(cfg)
+	if err != nil {
+		log.Error(ctx, "Failed to create planner: %v", err)
+		os.Exit(1)
+	}
+
+	log.Info(ctx, "Running in DRY-RUN mode: nothing is executed, written or moved")
+	log.Info(ctx, "Files to plan: %d", len(validPaths))
+	log.Info(ctx, "========================================")
+
+	for i, videoPath := range validPaths {
+		plan, err := planner.Plan(ctx, videoPath)
+		if err != nil {
+			log.Error(ctx, "[%d/%d] %s: %v", i+1, len(validPaths), videoPath, err)
+			continue
+		}
+
+		log.Info(ctx, "[%d/%d] %s", i+1, len(validPaths), plan.VideoPath)
+		for _, step := range plan.Steps {
+			log.Info(ctx, "  %s:", step.Stage)
+			for _, cmd := range step.Commands {
+				log.Info(ctx, "    $ %s", cmd)
+			}
+			for _, action := range step.Actions {
+				log.Info(ctx, "    - %s", action)
+			}
+		}
+	}
+	log.Info(ctx, "========================================")
+}
 
+// runTargetMode processes target files concurrently using goroutines
+func runTargetMode(ctx context.Context, cfg *config.Config, proc processor.Processor, log logger.Logger, target string) {
+	startTime := time.Now()
+
+	// Commands run in their own process groups, so Ctrl+C must cancel them explicitly
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	validPaths := resolveTargets(ctx, cfg, log, target)
 	if len(validPaths) == 0 {
 		log.Error(ctx, "No valid files to process")
 		return
@@ -144,34 +243,111 @@ func runTargetMode(ctx context.Context, cfg *config.Config, proc processor.Proce
 	log.Info(ctx, "========================================")
 }
 
-// runSummarize reads SRT files from output and generates a markdown summary via Gemini
-func runSummarize(ctx context.Context, cfg *config.Config, log logger.Logger) {
-	keysEnv := os.Getenv("GEMINI_API_KEYS")
-	if keysEnv == "" {
-		log.Error(ctx, "GEMINI_API_KEYS environment variable is not set")
-		log.Error(ctx, "Usage: export GEMINI_API_KEYS=\"key1,key2,key3\"")
+// loadLLMKeys reads the API keys from llm.keys_env. Gemini exits when no key is available;
+// openai and ollama send requests without a key instead.
+func loadLLMKeys(ctx context.Context, cfg *config.Config, log logger.Logger) []string {
+	keys, err := llm.KeysFromEnv(cfg.LLM.KeysEnv)
+	if err != nil {
+		if cfg.LLM.Provider != config.ProviderGemini {
+			log.Info(ctx, "No API keys in %s, sending %s requests without a key", cfg.LLM.KeysEnv, cfg.LLM.Provider)
+			return nil
+		}
+		log.Error(ctx, "%v", err)
+		log.Error(ctx, "Usage: export %s=\"key1,key2,key3\"", cfg.LLM.KeysEnv)
 		os.Exit(1)
 	}
+	return keys
+}
 
-	var keys []string
-	for _, k := range strings.Split(keysEnv, ",") {
-		k = strings.TrimSpace(k)
-		if k != "" {
-			keys = append(keys, k)
-		}
+// setupLLM returns the LLM client shared by every feature that uses one, or nil when
+// summarize mode, translation and chapters are all off
+func setupLLM(ctx context.Context, cfg *config.Config, summarize bool, m metrics.Metrics, log logger.Logger) llm.Client {
+	if !summarize && !cfg.Translation.Enabled && !cfg.Chapters.Enabled {
+		return nil
 	}
+	keys := loadLLMKeys(ctx, cfg, log)
+	log.Info(ctx, "LLM: %s %s (%d API keys)", cfg.LLM.Provider, cfg.LLMModel(), len(keys))
+	return llm.New(cfg, keys, m, log)
+}
 
-	if len(keys) == 0 {
-		log.Error(ctx, "No valid API keys found in GEMINI_API_KEYS")
+// setupTranslator returns the subtitle translator, or nil when translation is disabled
+func setupTranslator(ctx context.Context, cfg *config.Config, client llm.Client, log logger.Logger) translator.Translator {
+	if !cfg.Translation.Enabled {
+		return nil
+	}
+	log.Info(ctx, "Translation enabled: %s (burn: %s)", cfg.Translation.Language, cfg.Translation.Burn)
+	return translator.New(client, cfg.Translation.BatchSize, log)
+}
+
+// setupChapters returns the chapter generator, or nil when chapters are disabled
+func setupChapters(ctx context.Context, cfg *config.Config, client llm.Client, log logger.Logger) chapters.Generator {
+	if !cfg.Chapters.Enabled {
+		return nil
+	}
+	prompt, err := chapters.LoadPrompt(cfg.Gemini.Prompts)
+	if err != nil {
+		log.Error(ctx, "Failed to load chapters prompt: %v", err)
+		os.Exit(1)
+	}
+	log.Info(ctx, "Chapters enabled: at most %d, at least %s each", cfg.Chapters.MaxChapters, cfg.Chapters.MinLength)
+	return chapters.New(client, prompt, cfg.Chapters, log)
+}
+
+// loadGlossary reads whisper.glossary; a broken glossary stops the pipeline
+// rather than silently leaving terms uncorrected
+func loadGlossary(ctx context.Context, cfg *config.Config, log logger.Logger) *glossary.Glossary {
+	if cfg.Whisper.Glossary == "" {
+		return nil
+	}
+	gl, err := glossary.Load(cfg.Whisper.Glossary)
+	if err != nil {
+		log.Error(ctx, "Failed to load glossary: %v", err)
 		os.Exit(1)
 	}
+	log.Info(ctx, "Glossary: %d terms from %s", len(gl.Terms), cfg.Whisper.Glossary)
+	return gl
+}
+
+// setupCache opens the transcript cache when enabled; nil disables it
+func setupCache(ctx context.Context, cfg *config.Config, log logger.Logger) cache.Cache {
+	if !cfg.Cache.Enabled {
+		return nil
+	}
+	tc, err := cache.New(cfg.Paths.Cache)
+	if err != nil {
+		log.Warn(ctx, "Transcript cache disabled: %v", err)
+		return nil
+	}
+	log.Info(ctx, "Transcript cache: %s (hash: %s, skip duplicates: %v)", cfg.Paths.Cache, cfg.Cache.Hash, cfg.Cache.SkipDuplicates)
+	return tc
+}
+
+// runSummarize reads SRT files from output and generates a markdown summary via the configured LLM
+func runSummarize(ctx context.Context, cfg *config.Config, client llm.Client, gl *glossary.Glossary, m metrics.Metrics, log logger.Logger) {
+	prompts, err := summarizer.LoadPrompts(cfg.Gemini.Prompts, cfg.Gemini.Style)
+	if err != nil {
+		log.Error(ctx, "Failed to load prompts: %v", err)
+		os.Exit(1)
+	}
+	var terms []string
+	if gl != nil {
+		for _, t := range gl.Terms {
+			terms = append(terms, t.Term)
+		}
+	}
 
 	log.Info(ctx, "Running in SUMMARIZE mode")
-	log.Info(ctx, "API keys loaded: %d", len(keys))
 	log.Info(ctx, "Source: %s/*.srt", cfg.Paths.Output)
+	log.Info(ctx, "Prompt style: %s (%s)", prompts.Style, cfg.Gemini.Prompts)
 	log.Info(ctx, "========================================")
 
-	sum := summarizer.New(keys, cfg.Gemini.Model, log)
+	sum := summarizer.New(client, summarizer.Options{
+		Prompts:   prompts,
+		Language:  cfg.Gemini.Language,
+		Headings:  summarizer.Headings{KeyTerms: cfg.Gemini.Headings.KeyTerms, Notes: cfg.Gemini.Headings.Notes},
+		Glossary:  terms,
+		MaxTokens: cfg.Gemini.MaxInputTokens,
+	}, m, log)
 
 	startTime := time.Now()
 	if err := sum.SummarizeAll(ctx, cfg.Paths.Output); err != nil {
@@ -188,39 +364,50 @@ func runSummarize(ctx context.Context, cfg *config.Config, log logger.Logger) {
 	log.Info(ctx, "========================================")
 }
 
-// discoverVideoFiles scans the input directory for all video files
+// discoverVideoFiles scans the input directory tree for all video files.
+// Returned paths are relative to the input directory.
 func discoverVideoFiles(ctx context.Context, cfg *config.Config, log logger.Logger) []string {
 	supportedExts := map[string]bool{
 		".mp4": true, ".mov": true, ".avi": true,
 		".mkv": true, ".webm": true, ".m4v": true, ".flv": true,
 	}
 
-	files, err := os.ReadDir(cfg.Paths.Input)
-	if err != nil {
-		log.Error(ctx, "Failed to read input directory: %v", err)
-		return nil
-	}
-
 	var videos []string
-	for _, f := range files {
-		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
-			continue
+	err := filepath.WalkDir(cfg.Paths.Input, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-		if supportedExts[strings.ToLower(filepath.Ext(f.Name()))] {
-			videos = append(videos, f.Name())
+		if path != cfg.Paths.Input && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
+		if d.IsDir() || !supportedExts[strings.ToLower(filepath.Ext(d.Name()))] {
+			return nil
+		}
+		rel, err := filepath.Rel(cfg.Paths.Input, path)
+		if err != nil {
+			return err
+		}
+		videos = append(videos, rel)
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to read input directory: %v", err)
+		return nil
 	}
 	return videos
 }
 
 // runWatchMode monitors input folder for new files
-func runWatchMode(ctx context.Context, cfg *config.Config, proc processor.Processor, log logger.Logger) {
+func runWatchMode(ctx context.Context, cfg *config.Config, proc processor.Processor, store jobstore.Store, m metrics.Metrics, log logger.Logger) {
 	log.Info(ctx, "Running in WATCH mode")
 	log.Info(ctx, "Max Concurrent Processing: %d", cfg.Performance.MaxConcurrent)
 	log.Info(ctx, "========================================")
 
 	// Create watcher with processor as handler and concurrency control
-	w, err := watcher.New(cfg.Paths.Input, proc.Process, log, cfg.Performance.MaxConcurrent)
+	w, err := watcher.New(cfg.Paths.Input, cfg.Watcher, proc.Process, m, log, cfg.Performance.MaxConcurrent)
 	if err != nil {
 		log.Error(ctx, "Failed to create watcher: %v", err)
 		os.Exit(1)
@@ -243,12 +430,15 @@ func runWatchMode(ctx context.Context, cfg *config.Config, proc processor.Proces
 		}
 	}()
 
+	// Pick up jobs interrupted by a previous shutdown or crash
+	resumePendingJobs(ctx, cfg, store, w, log)
+
 	log.Info(ctx, "Video Pipeline is ready!")
 	log.Info(ctx, "Monitoring: %s", cfg.Paths.Input)
 	log.Info(ctx, "Output: %s", cfg.Paths.Output)
 	log.Info(ctx, "")
 	log.Info(ctx, "Optimizations:")
-	log.Info(ctx, "  - Whisper: %d threads, Metal GPU", cfg.Whisper.Threads)
+	log.Info(ctx, "  - Whisper: %s backend, %d threads", cfg.Whisper.Backend, cfg.Whisper.Threads)
 	log.Info(ctx, "  - FFmpeg: %s encoder, %s bitrate", cfg.FFmpeg.Encoder, cfg.FFmpeg.VideoBitrate)
 	log.Info(ctx, "  - Concurrent: %d videos at once", cfg.Performance.MaxConcurrent)
 	log.Info(ctx, "")
@@ -270,6 +460,62 @@ func runWatchMode(ctx context.Context, cfg *config.Config, proc processor.Proces
 	log.Info(ctx, "Video Pipeline stopped")
 }
 
+// runServeMode exposes the pipeline through the HTTP job API
+func runServeMode(ctx context.Context, cfg *config.Config, proc processor.Processor, store jobstore.Store, tracker progress.Tracker, log logger.Logger) {
+	log.Info(ctx, "Running in SERVE mode")
+	log.Info(ctx, "Max Concurrent Processing: %d", cfg.Performance.MaxConcurrent)
+	log.Info(ctx, "========================================")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	srv := api.New(cfg, proc, store, tracker, log)
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- srv.Start(ctx)
+	}()
+
+	log.Info(ctx, "Endpoints:")
+	log.Info(ctx, "  POST /jobs                          # {\"path\": \"video.mp4\"} or multipart upload (field \"file\")")
+	log.Info(ctx, "  GET  /jobs                          # List jobs")
+	log.Info(ctx, "  GET  /jobs/{id}                     # Job status per stage")
+	log.Info(ctx, "  POST /jobs/{id}/cancel              # Cancel a queued or running job")
+	log.Info(ctx, "  GET  /jobs/{id}/artifacts/{name}    # Download video, srt, vtt, transcript, summary, ...")
+	log.Info(ctx, "Press Ctrl+C to stop")
+	log.Info(ctx, "========================================")
+
+	select {
+	case <-sigChan:
+		log.Info(ctx, "Shutdown signal received")
+		log.Info(ctx, "Shutting down gracefully...")
+		cancel()
+		<-errChan
+	case err := <-errChan:
+		if err != nil && err != context.Canceled {
+			log.Error(ctx, "Server error: %v", err)
+		}
+	}
+
+	log.Info(ctx, "Video Pipeline stopped")
+}
+
+// resumePendingJobs resubmits unfinished jobs whose source video is still in the input tree.
+// Watch mode only reacts to new files, so without this a restart would strand them.
+func resumePendingJobs(ctx context.Context, cfg *config.Config, store jobstore.Store, w watcher.Watcher, log logger.Logger) {
+	jobs, err := jobstore.Resumable(ctx, store, cfg.Paths.Input)
+	if err != nil {
+		log.Warn(ctx, "Failed to list jobs for resume: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		log.Info(ctx, "Resuming unfinished job %s: %s", job.ID, filepath.Base(job.VideoPath))
+		w.Submit(ctx, job.VideoPath)
+	}
+}
+
 // showUsage displays available files and usage instructions
 func showUsage(ctx context.Context, cfg *config.Config, log logger.Logger) {
 	log.Info(ctx, "Usage:")
@@ -277,6 +523,9 @@ func showUsage(ctx context.Context, cfg *config.Config, log logger.Logger) {
 	log.Info(ctx, "  ./vid-pipeline -target <filename>     # Process specific file(s)")
 	log.Info(ctx, "  ./vid-pipeline -watch                 # Watch mode (monitor folder)")
 	log.Info(ctx, "  ./vid-pipeline -summarize             # Generate transcript + summary DOCX")
+	log.Info(ctx, "  ./vid-pipeline -summarize -style <s>  # Summary in another prompt style")
+	log.Info(ctx, "  ./vid-pipeline -serve                 # HTTP job API on server.addr")
+	log.Info(ctx, "  ./vid-pipeline -dry-run [-target ...] # Print the plan without running anything")
 	log.Info(ctx, "")
 	log.Info(ctx, "Available files in %s:", cfg.Paths.Input)
 
@@ -308,9 +557,30 @@ func showUsage(ctx context.Context, cfg *config.Config, log logger.Logger) {
 	log.Info(ctx, "Examples:")
 	log.Info(ctx, "  ./vid-pipeline -target \"video.mp4\"")
 	log.Info(ctx, "  ./vid-pipeline -target \"video1.mp4,video2.mp4\"")
+	log.Info(ctx, "  ./vid-pipeline -target \"course-a/lesson1.mp4\"")
 	log.Info(ctx, "  ./vid-pipeline -watch")
 }
 
+// setupMetrics starts the Prometheus endpoint when metrics are enabled,
+// otherwise returns a no-op recorder
+func setupMetrics(ctx context.Context, cfg *config.Config, log logger.Logger) metrics.Metrics {
+	if !cfg.Metrics.Enabled {
+		return metrics.NewNop()
+	}
+
+	m := metrics.New()
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Metrics.Path, m.Handler())
+
+	go func() {
+		log.Info(ctx, "Metrics endpoint: http://%s%s", cfg.Metrics.Addr, cfg.Metrics.Path)
+		if err := http.ListenAndServe(cfg.Metrics.Addr, mux); err != nil {
+			log.Error(ctx, "Metrics endpoint stopped: %v", err)
+		}
+	}()
+	return m
+}
+
 // ensureDirectories creates required directories if they don't exist
 func ensureDirectories(cfg *config.Config) error {
 	dirs := []string{
@@ -322,6 +592,10 @@ func ensureDirectories(cfg *config.Config) error {
 		filepath.Join(cfg.Paths.Output, "archived"),
 		cfg.Paths.Archived,
 		cfg.Paths.Temp,
+		cfg.Paths.Jobs,
+	}
+	if cfg.Cache.Enabled {
+		dirs = append(dirs, cfg.Paths.Cache)
 	}
 
 	for _, dir := range dirs {
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+// artifacts maps downloadable artifact names to files that currently exist for job:
+// "video" (burned and/or muxed output), one entry per exported subtitle format ("srt", "vtt", ...),
+// "translation" (<name>.<lang>.srt), "filter_report" (<name>.filtered.txt),
+// "chapters" (<name>.chapters.txt) and "chapters_vtt" (<name>.chapters.vtt),
+// "original" (archived source), the "transcript"/"summary" DOCX from -summarize and the
+// summary as "summary_md", "summary_html" and "summary_json"
+func (s *implServer) artifacts(job *jobstore.Job) map[string]string {
+	out := make(map[string]string)
+
+	if rec, ok := job.Completed(jobstore.StageFilter); ok {
+		out["filter_report"] = rec.Artifacts["report"]
+	}
+	if rec, ok := job.Completed(jobstore.StageTranslate); ok {
+		out["translation"] = rec.Artifacts["translation"]
+	}
+	if rec, ok := job.Completed(jobstore.StageChapters); ok {
+		out["chapters"] = rec.Artifacts["youtube"]
+		out["chapters_vtt"] = rec.Artifacts["vtt"]
+	}
+	if rec, ok := job.Completed(jobstore.StageBurn); ok {
+		out["video"] = rec.Artifacts["video"]
+	}
+	if rec, ok := job.Completed(jobstore.StageMux); ok {
+		out["video"] = rec.Artifacts["video"]
+	}
+	if rec, ok := job.Completed(jobstore.StageExport); ok {
+		for format, path := range rec.Artifacts {
+			out[format] = path
+		}
+	}
+	if rec, ok := job.Completed(jobstore.StageArchive); ok {
+		out["original"] = rec.Artifacts["video"]
+	}
+
+	// The summarizer mirrors the SRT's location below transcripts/ and summaries/
+	if srt, ok := out["srt"]; ok {
+		if relDir, err := filepath.Rel(s.cfg.Paths.Output, filepath.Dir(srt)); err == nil {
+			name := strings.TrimSuffix(filepath.Base(srt), filepath.Ext(srt))
+			summary := filepath.Join(s.cfg.Paths.Output, "summaries", relDir, name)
+			out["transcript"] = filepath.Join(s.cfg.Paths.Output, "transcripts", relDir, name+".docx")
+			out["summary"] = summary + ".docx"
+			out["summary_md"] = summary + ".md"
+			out["summary_html"] = summary + ".html"
+			out["summary_json"] = summary + ".json"
+		}
+	}
+
+	for name, path := range out {
+		if _, err := os.Stat(path); err != nil {
+			delete(out, name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+// handleSubmit creates a job for an existing input file or an uploaded video and queues it
+func (s *implServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	req, err := s.processSubmitRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := s.store.Open(r.Context(), req.Path)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Leave a queued or running job's overrides alone; the processor owns its record
+	if s.busy(job.ID) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("job %s is already queued or running", job.ID))
+		return
+	}
+	// Each submission sets the overrides afresh so a rerun does not inherit old ones
+	job.Mode, job.Style = req.Mode, req.Style
+	if err := s.store.Save(r.Context(), job); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !s.enqueue(job) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("job %s is already queued or running", job.ID))
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, s.newJobResponse(job))
+}
+
+// handleList returns all known jobs
+func (s *implServer) handleList(w http.ResponseWriter, r *http.Request) {
+	jobs, err := s.store.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := make([]jobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		resp = append(resp, s.newJobResponse(job))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleGet returns one job with per-stage status and errors
+func (s *implServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.loadJob(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.newJobResponse(job))
+}
+
+// handleCancel stops a queued or running job
+func (s *implServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.loadJob(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.cancel(job.ID) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("job %s is not queued or running", job.ID))
+		return
+	}
+	writeJSON(w, http.StatusAccepted, s.newJobResponse(job))
+}
+
+// handleArtifact downloads one of the job's output files
+func (s *implServer) handleArtifact(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.loadJob(w, r)
+	if !ok {
+		return
+	}
+
+	name := r.PathValue("name")
+	path, ok := s.artifacts(job)[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("artifact %q not available", name))
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(path)))
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), f)
+}
+
+// loadJob fetches the job named in the URL, writing the error response itself on failure
+func (s *implServer) loadJob(w http.ResponseWriter, r *http.Request) (*jobstore.Job, bool) {
+	job, err := s.store.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, jobstore.ErrJobNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return nil, false
+	}
+	return job, true
+}
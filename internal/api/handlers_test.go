@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/progress"
+)
+
+// fakeProcessor writes an SRT checkpoint like the real export stage, or blocks until canceled
+type fakeProcessor struct {
+	cfg   *config.Config
+	store jobstore.Store
+	block bool
+}
+
+func (f *fakeProcessor) Process(ctx context.Context, videoPath string) error {
+	if f.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	job, err := f.store.Open(ctx, videoPath)
+	if err != nil {
+		return err
+	}
+	srt := filepath.Join(f.cfg.Paths.Output, "video.srt")
+	if err := os.WriteFile(srt, []byte("1\n00:00:00,000 --> 00:00:01,000\nhi\n\n"), 0644); err != nil {
+		return err
+	}
+	job.CompleteStage(jobstore.StageExport, map[string]string{"srt": srt})
+	job.Finish(nil)
+	return f.store.Save(ctx, job)
+}
+
+func newTestServer(t *testing.T, block bool) (*implServer, *config.Config) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Paths: config.PathsConfig{
+			Input:  filepath.Join(dir, "input"),
+			Output: filepath.Join(dir, "output"),
+		},
+		Performance: config.PerformanceConfig{MaxConcurrent: 1},
+	}
+	for _, d := range []string{cfg.Paths.Input, cfg.Paths.Output} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(cfg.Paths.Input, "video.mp4"), []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := jobstore.New(filepath.Join(dir, "jobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(cfg, &fakeProcessor{cfg: cfg, store: store, block: block}, store, progress.New(), logger.New("error")).(*implServer)
+	t.Cleanup(srv.wg.Wait)
+	return srv, cfg
+}
+
+func doRequest(t *testing.T, srv *implServer, method, url string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, url, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	srv.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func waitForStatus(t *testing.T, srv *implServer, id string, want jobstore.Status) jobResponse {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec := doRequest(t, srv, http.MethodGet, "/jobs/"+id, nil, "")
+		var job jobResponse
+		if err := json.NewDecoder(rec.Body).Decode(&job); err != nil {
+			t.Fatal(err)
+		}
+		if job.Status == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %v, want %v", job.Status, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubmitAndDownload(t *testing.T) {
+	srv, _ := newTestServer(t, false)
+
+	rec := doRequest(t, srv, http.MethodPost, "/jobs", strings.NewReader(`{"path": "video.mp4"}`), "application/json")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /jobs = %d: %s", rec.Code, rec.Body)
+	}
+	var submitted jobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&submitted); err != nil {
+		t.Fatal(err)
+	}
+
+	job := waitForStatus(t, srv, submitted.ID, jobstore.StatusCompleted)
+	if len(job.Stages) != len(jobstore.Stages) {
+		t.Errorf("len(Stages) = %d, want %d", len(job.Stages), len(jobstore.Stages))
+	}
+	if len(job.Artifacts) != 1 || job.Artifacts[0] != "srt" {
+		t.Errorf("Artifacts = %v, want [srt]", job.Artifacts)
+	}
+
+	rec = doRequest(t, srv, http.MethodGet, "/jobs/"+submitted.ID+"/artifacts/srt", nil, "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "hi") {
+		t.Errorf("GET artifact = %d: %q", rec.Code, rec.Body)
+	}
+
+	rec = doRequest(t, srv, http.MethodGet, "/jobs/"+submitted.ID+"/artifacts/video", nil, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET missing artifact = %d, want 404", rec.Code)
+	}
+
+	rec = doRequest(t, srv, http.MethodGet, "/jobs", nil, "")
+	var jobs []jobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&jobs); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 {
+		t.Errorf("GET /jobs returned %d jobs, want 1", len(jobs))
+	}
+}
+
+func TestCancel(t *testing.T) {
+	srv, _ := newTestServer(t, true)
+
+	rec := doRequest(t, srv, http.MethodPost, "/jobs", strings.NewReader(`{"path": "video.mp4"}`), "application/json")
+	var submitted jobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&submitted); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/jobs", strings.NewReader(`{"path": "video.mp4"}`), "application/json")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("duplicate submit = %d, want 409", rec.Code)
+	}
+
+	rec = doRequest(t, srv, http.MethodPost, "/jobs/"+submitted.ID+"/cancel", nil, "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("cancel = %d: %s", rec.Code, rec.Body)
+	}
+	waitForStatus(t, srv, submitted.ID, jobstore.StatusCanceled)
+}
+
+func TestResumePending(t *testing.T) {
+	srv, cfg := newTestServer(t, false)
+	ctx := context.Background()
+
+	// A job left running by a crash
+	job, err := srv.store.Open(ctx, filepath.Join(cfg.Paths.Input, "video.mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.StartStage(jobstore.StageExtract)
+	if err := srv.store.Save(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.resumePending(ctx)
+	waitForStatus(t, srv, job.ID, jobstore.StatusCompleted)
+}
+
+func TestUpload(t *testing.T) {
+	srv, cfg := newTestServer(t, false)
+
+	upload := func(target string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, err := mw.CreateFormFile("file", "lesson.mp4")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("uploaded video"))
+		mw.Close()
+		return doRequest(t, srv, http.MethodPost, target, &body, mw.FormDataContentType())
+	}
+
+	// Bad overrides are rejected before anything is written
+	rec := upload("/jobs?folder=courses/go&mode=embed")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("upload with invalid mode = %d, want 400", rec.Code)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Paths.Input, "courses", "go", "lesson.mp4")); !os.IsNotExist(err) {
+		t.Errorf("upload with invalid mode was saved")
+	}
+
+	rec = upload("/jobs?folder=courses/go&mode=burn&style=" + url.QueryEscape(`{"font_size": 28}`))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("upload = %d: %s", rec.Code, rec.Body)
+	}
+	var submitted jobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&submitted); err != nil {
+		t.Fatal(err)
+	}
+	if submitted.Mode != config.ModeBurn || submitted.Style == nil || submitted.Style.FontSize != 28 {
+		t.Errorf("overrides = %q %+v, want burn with font_size 28", submitted.Mode, submitted.Style)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.Paths.Input, "courses", "go", "lesson.mp4"))
+	if err != nil {
+		t.Fatalf("uploaded file not saved: %v", err)
+	}
+	if string(data) != "uploaded video" {
+		t.Errorf("uploaded content = %q", data)
+	}
+}
+
+func TestSubmitOverrides(t *testing.T) {
+	srv, _ := newTestServer(t, false)
+
+	body := `{"path": "video.mp4", "mode": "mux", "style": {"font_size": 32, "primary_color": "#FFCC00"}}`
+	rec := doRequest(t, srv, http.MethodPost, "/jobs", strings.NewReader(body), "application/json")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /jobs = %d: %s", rec.Code, rec.Body)
+	}
+	var submitted jobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&submitted); err != nil {
+		t.Fatal(err)
+	}
+	waitForStatus(t, srv, submitted.ID, jobstore.StatusCompleted)
+
+	job, err := srv.store.Get(context.Background(), submitted.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Mode != config.ModeMux {
+		t.Errorf("Mode = %q, want %q", job.Mode, config.ModeMux)
+	}
+	if job.Style == nil || job.Style.FontSize != 32 || job.Style.PrimaryColor != "#FFCC00" {
+		t.Errorf("Style = %+v, want font_size 32 and primary_color #FFCC00", job.Style)
+	}
+
+	// Resubmitting without overrides clears them
+	rec = doRequest(t, srv, http.MethodPost, "/jobs", strings.NewReader(`{"path": "video.mp4"}`), "application/json")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("POST /jobs = %d: %s", rec.Code, rec.Body)
+	}
+	waitForStatus(t, srv, submitted.ID, jobstore.StatusCompleted)
+	job, err = srv.store.Get(context.Background(), submitted.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Mode != "" || job.Style != nil {
+		t.Errorf("overrides = %q %+v, want none", job.Mode, job.Style)
+	}
+}
+
+func TestSubmitErrors(t *testing.T) {
+	srv, _ := newTestServer(t, false)
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		wantCode int
+	}{
+		{"missing file", http.MethodPost, "/jobs", `{"path": "nope.mp4"}`, http.StatusBadRequest},
+		{"path escape", http.MethodPost, "/jobs", `{"path": "../video.mp4"}`, http.StatusBadRequest},
+		{"absolute path", http.MethodPost, "/jobs", `{"path": "/etc/passwd"}`, http.StatusBadRequest},
+		{"invalid mode", http.MethodPost, "/jobs", `{"path": "video.mp4", "mode": "embed"}`, http.StatusBadRequest},
+		{"invalid style", http.MethodPost, "/jobs", `{"path": "video.mp4", "style": {"primary_color": "yellow"}}`, http.StatusBadRequest},
+		{"negative style margin", http.MethodPost, "/jobs", `{"path": "video.mp4", "style": {"margin_v": -20}}`, http.StatusBadRequest},
+		{"unknown job", http.MethodGet, "/jobs/deadbeef", "", http.StatusNotFound},
+		{"cancel unknown job", http.MethodPost, "/jobs/deadbeef/cancel", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(t, srv, tt.method, tt.url, strings.NewReader(tt.body), "application/json")
+			if rec.Code != tt.wantCode {
+				t.Errorf("%s %s = %d, want %d (%s)", tt.method, tt.url, rec.Code, tt.wantCode, rec.Body)
+			}
+		})
+	}
+}
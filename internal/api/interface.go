@@ -0,0 +1,9 @@
+package api
+
+import "context"
+
+// Server defines the interface for the HTTP job API
+type Server interface {
+	// Start serves the API until ctx is cancelled, then shuts down and waits for running jobs
+	Start(ctx context.Context) error
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/processor"
+	"github.com/nguyentantai21042004/caption-flow/internal/progress"
+)
+
+type implServer struct {
+	cfg       *config.Config
+	processor processor.Processor
+	store     jobstore.Store
+	progress  progress.Tracker
+	logger    logger.Logger
+	semaphore chan struct{}
+	handler   http.Handler
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc // queued or running jobs by ID
+	wg      sync.WaitGroup
+	baseCtx context.Context
+}
+
+// New creates a new API Server. Jobs share the processor and the
+// performance.max_concurrent limit with the other modes.
+func New(cfg *config.Config, proc processor.Processor, store jobstore.Store, tracker progress.Tracker, log logger.Logger) Server {
+	maxConcurrent := cfg.Performance.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = 2
+	}
+
+	s := &implServer{
+		cfg:       cfg,
+		processor: proc,
+		store:     store,
+		progress:  tracker,
+		logger:    log,
+		semaphore: make(chan struct{}, maxConcurrent),
+		cancels:   make(map[string]context.CancelFunc),
+		baseCtx:   context.Background(),
+	}
+	s.handler = s.routes()
+	return s
+}
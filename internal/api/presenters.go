@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+type stageResponse struct {
+	Name        jobstore.Stage  `json:"name"`
+	Status      jobstore.Status `json:"status"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// progressResponse is the live progress of the stage a running job is in
+type progressResponse struct {
+	Stage      string    `json:"stage"`
+	Percent    float64   `json:"percent"`
+	ETASeconds int64     `json:"eta_seconds,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type jobResponse struct {
+	ID        string          `json:"id"`
+	VideoPath string          `json:"video_path"`
+	Status    jobstore.Status `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	// DuplicateOf is set when the job was skipped as a duplicate of an earlier video
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	// Mode and Style are the overrides given on submission, if any
+	Mode      string                      `json:"mode,omitempty"`
+	Style     *config.SubtitleStyleConfig `json:"style,omitempty"`
+	Stages    []stageResponse             `json:"stages"`
+	Artifacts []string                    `json:"artifacts"`
+	// Progress is set while the job is running and its current stage reports progress
+	Progress  *progressResponse `json:"progress,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// newJobResponse presents a job with its stages in pipeline order
+func (s *implServer) newJobResponse(job *jobstore.Job) jobResponse {
+	resp := jobResponse{
+		ID:          job.ID,
+		VideoPath:   job.VideoPath,
+		Status:      job.Status,
+		Error:       job.Error,
+		DuplicateOf: job.DuplicateOf,
+		Mode:        job.Mode,
+		Style:       job.Style,
+		Stages:      make([]stageResponse, 0, len(jobstore.Stages)),
+		Artifacts:   make([]string, 0),
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+
+	for _, stage := range jobstore.Stages {
+		sr := stageResponse{Name: stage, Status: jobstore.StatusPending}
+		if rec, ok := job.Stages[stage]; ok {
+			sr.Status = rec.Status
+			sr.Error = rec.Error
+			if !rec.StartedAt.IsZero() {
+				sr.StartedAt = &rec.StartedAt
+			}
+			if !rec.CompletedAt.IsZero() {
+				sr.CompletedAt = &rec.CompletedAt
+			}
+		}
+		resp.Stages = append(resp.Stages, sr)
+	}
+
+	if p, ok := s.progress.Get(job.ID); ok && job.Status == jobstore.StatusRunning {
+		resp.Progress = &progressResponse{
+			Stage:      p.Stage,
+			Percent:    math.Round(p.Percent*10) / 10,
+			ETASeconds: int64(p.ETA.Seconds()),
+			UpdatedAt:  p.UpdatedAt,
+		}
+	}
+
+	for name := range s.artifacts(job) {
+		resp.Artifacts = append(resp.Artifacts, name)
+	}
+	sort.Strings(resp.Artifacts)
+
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
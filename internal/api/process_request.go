@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+)
+
+var supportedExts = map[string]bool{
+	".mp4": true, ".mov": true, ".avi": true,
+	".mkv": true, ".webm": true, ".m4v": true, ".flv": true,
+}
+
+// submitRequest is the JSON body for submitting a file already in the input folder.
+// Mode and Style optionally override subtitles.mode and the caption style for this job.
+type submitRequest struct {
+	Path  string                      `json:"path"`
+	Mode  string                      `json:"mode"`
+	Style *config.SubtitleStyleConfig `json:"style"`
+}
+
+// processSubmitRequest resolves the video and overrides for POST /jobs: either a JSON body
+// naming a file relative to paths.input, or a multipart upload (field "file") streamed into
+// paths.input, optionally below the subfolder given by the "folder" query parameter. Uploads
+// take their overrides from the "mode" and "style" (JSON) query parameters.
+func (s *implServer) processSubmitRequest(r *http.Request) (submitRequest, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		req := submitRequest{Mode: r.URL.Query().Get("mode")}
+		if raw := r.URL.Query().Get("style"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Style); err != nil {
+				return submitRequest{}, fmt.Errorf("decode style: %w", err)
+			}
+		}
+		// Reject bad overrides before the upload is written to disk
+		if err := s.validateOverrides(req); err != nil {
+			return submitRequest{}, err
+		}
+		videoPath, err := s.saveUpload(r)
+		if err != nil {
+			return submitRequest{}, err
+		}
+		req.Path = videoPath
+		return req, nil
+	}
+
+	var req submitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return submitRequest{}, fmt.Errorf("decode request: %w", err)
+	}
+	if req.Path == "" {
+		return submitRequest{}, fmt.Errorf("path is required")
+	}
+	if err := s.validateOverrides(req); err != nil {
+		return submitRequest{}, err
+	}
+
+	videoPath, err := s.inputPath(req.Path)
+	if err != nil {
+		return submitRequest{}, err
+	}
+	info, err := os.Stat(videoPath)
+	if err != nil || info.IsDir() {
+		return submitRequest{}, fmt.Errorf("file not found: %s", req.Path)
+	}
+	if !supportedExts[strings.ToLower(filepath.Ext(videoPath))] {
+		return submitRequest{}, fmt.Errorf("unsupported video format: %s", req.Path)
+	}
+	req.Path = videoPath
+	return req, nil
+}
+
+// validateOverrides checks a job's mode and style the same way config.Validate checks routes
+func (s *implServer) validateOverrides(req submitRequest) error {
+	if req.Mode != "" && !config.ValidMode(req.Mode) {
+		return fmt.Errorf("mode %q is not supported (burn, mux, both)", req.Mode)
+	}
+	if req.Style != nil {
+		if _, err := s.cfg.SubtitleStyle.Merge(*req.Style).ASSStyle(); err != nil {
+			return fmt.Errorf("style: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveUpload streams the uploaded file into the input folder without buffering it in memory
+func (s *implServer) saveUpload(r *http.Request) (string, error) {
+	folder := r.URL.Query().Get("folder")
+	destDir, err := s.inputPath(folder)
+	if err != nil {
+		return "", err
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return "", fmt.Errorf("read multipart: %w", err)
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return "", fmt.Errorf("multipart field \"file\" is required")
+		}
+		if err != nil {
+			return "", fmt.Errorf("read multipart: %w", err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		filename := filepath.Base(part.FileName())
+		if filename == "." || filename == string(filepath.Separator) || strings.HasPrefix(filename, ".") {
+			return "", fmt.Errorf("invalid file name %q", part.FileName())
+		}
+		if !supportedExts[strings.ToLower(filepath.Ext(filename))] {
+			return "", fmt.Errorf("unsupported video format: %s", filename)
+		}
+
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return "", fmt.Errorf("create upload dir: %w", err)
+		}
+		destPath := filepath.Join(destDir, filename)
+		if _, err := os.Stat(destPath); err == nil {
+			return "", fmt.Errorf("file already exists: %s", filename)
+		}
+
+		// Hidden temp name so watch mode ignores the half-written upload
+		tmp, err := os.CreateTemp(destDir, ".upload-*")
+		if err != nil {
+			return "", fmt.Errorf("create upload file: %w", err)
+		}
+		if _, err := io.Copy(tmp, part); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return "", fmt.Errorf("save upload: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			os.Remove(tmp.Name())
+			return "", fmt.Errorf("save upload: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), destPath); err != nil {
+			os.Remove(tmp.Name())
+			return "", fmt.Errorf("save upload: %w", err)
+		}
+
+		s.logger.Info(r.Context(), "Upload saved: %s", destPath)
+		return destPath, nil
+	}
+}
+
+// inputPath resolves rel below paths.input, rejecting absolute paths and escapes
+func (s *implServer) inputPath(rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("path must be relative to the input folder: %s", rel)
+	}
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes the input folder: %s", rel)
+	}
+	return filepath.Join(s.cfg.Paths.Input, clean), nil
+}
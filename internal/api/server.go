@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+// Start serves the API on server.addr until ctx is cancelled
+func (s *implServer) Start(ctx context.Context) error {
+	s.baseCtx = ctx
+	s.resumePending(ctx)
+
+	srv := &http.Server{
+		Addr:              s.cfg.Server.Addr,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+	}()
+	s.logger.Info(ctx, "Job API listening on %s", s.cfg.Server.Addr)
+
+	select {
+	case <-ctx.Done():
+	case err := <-errChan:
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		s.logger.Warn(ctx, "HTTP shutdown: %v", err)
+	}
+
+	s.logger.Info(ctx, "Waiting for ongoing processing to complete...")
+	s.wg.Wait()
+	return ctx.Err()
+}
+
+// resumePending queues the unfinished jobs of an earlier run, so jobs that were running or
+// waiting for a slot when the server stopped continue from their last finished stage
+func (s *implServer) resumePending(ctx context.Context) {
+	jobs, err := jobstore.Resumable(ctx, s.store, s.cfg.Paths.Input)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to list jobs for resume: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		if s.enqueue(job) {
+			s.logger.Info(ctx, "Resuming unfinished job %s: %s", job.ID, filepath.Base(job.VideoPath))
+		}
+	}
+}
+
+// busy reports whether the job is queued or running
+func (s *implServer) busy(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.cancels[id]
+	return ok
+}
+
+// enqueue schedules the job in the background; it waits for a free slot under the
+// shared concurrency limit. Returns false if the job is already queued or running.
+func (s *implServer) enqueue(job *jobstore.Job) bool {
+	s.mu.Lock()
+	if _, busy := s.cancels[job.ID]; busy {
+		s.mu.Unlock()
+		return false
+	}
+	ctx, cancel := context.WithCancel(s.baseCtx)
+	s.cancels[job.ID] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			delete(s.cancels, job.ID)
+			s.mu.Unlock()
+			cancel()
+		}()
+
+		select {
+		case s.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			s.markCanceled(job.ID)
+			return
+		}
+		defer func() { <-s.semaphore }()
+
+		s.logger.Info(ctx, "[START] job %s: %s", job.ID, job.VideoPath)
+		if err := s.processor.Process(ctx, job.VideoPath); err != nil {
+			if ctx.Err() != nil && s.baseCtx.Err() == nil {
+				s.markCanceled(job.ID)
+				s.logger.Info(ctx, "[CANCELED] job %s", job.ID)
+				return
+			}
+			s.logger.Error(ctx, "[FAIL]  job %s: %v", job.ID, err)
+			return
+		}
+		s.logger.Info(ctx, "[DONE]  job %s", job.ID)
+	}()
+	return true
+}
+
+// cancel stops a queued or running job. Returns false if the job is not active.
+func (s *implServer) cancel(id string) bool {
+	s.mu.Lock()
+	cancel, ok := s.cancels[id]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// markCanceled records a user cancellation on the stored job
+func (s *implServer) markCanceled(id string) {
+	ctx := context.Background()
+	job, err := s.store.Get(ctx, id)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to load canceled job %s: %v", id, err)
+		return
+	}
+	job.Cancel()
+	if err := s.store.Save(ctx, job); err != nil {
+		s.logger.Warn(ctx, "Failed to save canceled job %s: %v", id, err)
+	}
+}
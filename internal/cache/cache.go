@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Lookup returns the entry for key when both its record and transcript are present
+func (c *implCache) Lookup(ctx context.Context, key Key) (*Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, err := c.read(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(entry.SRTPath); err != nil {
+		return nil, ErrNotFound
+	}
+	return entry, nil
+}
+
+// Store copies the transcript first and the record last, so a crash in between
+// never leaves a record pointing at a missing or partial transcript
+func (c *implCache) Store(ctx context.Context, key Key, srtPath, source string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(srtPath)
+	if err != nil {
+		return fmt.Errorf("read transcript: %w", err)
+	}
+	if err := c.writeFile(c.path(key, ".srt"), data); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return c.write(&Entry{Key: key, Source: source, CreatedAt: now, UpdatedAt: now})
+}
+
+// SetOutput records output on the existing entry for key
+func (c *implCache) SetOutput(ctx context.Context, key Key, output string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, err := c.read(key)
+	if err != nil {
+		return err
+	}
+	entry.Output = output
+	entry.UpdatedAt = time.Now()
+	return c.write(entry)
+}
+
+func (c *implCache) read(key Key) (*Entry, error) {
+	data, err := os.ReadFile(c.path(key, ".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("read cache entry: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("decode cache entry: %w", err)
+	}
+	// Guard against id collisions and hand-edited records
+	if entry.Key != key {
+		return nil, ErrNotFound
+	}
+	entry.SRTPath = c.path(key, ".srt")
+	return &entry, nil
+}
+
+func (c *implCache) write(entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+	return c.writeFile(c.path(entry.Key, ".json"), data)
+}
+
+// writeFile replaces path atomically via a temp file in the cache dir
+func (c *implCache) writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, ".cache-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("commit cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *implCache) path(key Key, ext string) string {
+	return filepath.Join(c.dir, key.id()+ext)
+}
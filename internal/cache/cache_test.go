@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreAndLookup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	c, err := New(filepath.Join(dir, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srt := filepath.Join(dir, "a.srt")
+	content := "1\n00:00:00,000 --> 00:00:01,000\nhello\n"
+	if err := os.WriteFile(srt, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	key := Key{Hash: "abc", Model: "ggml-large-v3-turbo.bin", Language: "en", Prompt: "Kubernetes"}
+	if _, err := c.Lookup(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Lookup() before Store error = %v, want ErrNotFound", err)
+	}
+	if err := c.Store(ctx, key, srt, "/in/a.mp4"); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if err := c.SetOutput(ctx, key, "/out/a.mp4"); err != nil {
+		t.Fatalf("SetOutput() error = %v", err)
+	}
+
+	entry, err := c.Lookup(ctx, key)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if entry.Source != "/in/a.mp4" || entry.Output != "/out/a.mp4" {
+		t.Errorf("entry = %+v", entry)
+	}
+	got, err := os.ReadFile(entry.SRTPath)
+	if err != nil || string(got) != content {
+		t.Errorf("cached transcript = %q, %v", got, err)
+	}
+
+	// Any differing field is a different transcript
+	for _, other := range []Key{
+		{Hash: "abd", Model: key.Model, Language: key.Language, Prompt: key.Prompt},
+		{Hash: key.Hash, Model: "ggml-base.bin", Language: key.Language, Prompt: key.Prompt},
+		{Hash: key.Hash, Model: key.Model, Language: "vi", Prompt: key.Prompt},
+		{Hash: key.Hash, Model: key.Model, Language: key.Language},
+	} {
+		if _, err := c.Lookup(ctx, other); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup(%+v) error = %v, want ErrNotFound", other, err)
+		}
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.mp4")
+	b := filepath.Join(dir, "renamed.mp4")
+	for _, path := range []string{a, b} {
+		if err := os.WriteFile(path, []byte("same bytes"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ha, err := HashFile(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, err := HashFile(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ha != hb {
+		t.Errorf("HashFile() differs for identical content: %s vs %s", ha, hb)
+	}
+}
@@ -0,0 +1,6 @@
+package cache
+
+import "errors"
+
+// ErrNotFound is returned when no transcript is cached under the requested key
+var ErrNotFound = errors.New("transcript not cached")
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// HashFile returns the hex SHA-256 of the file's content
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// id derives the file name for key; every field takes part so a different
+// model, language or prompt never reuses a transcript
+func (k Key) id() string {
+	h := sha256.New()
+	for _, field := range []string{k.Hash, k.Model, k.Language, k.Prompt} {
+		// Length-prefix the fields so ("ab", "c") and ("a", "bc") differ
+		fmt.Fprintf(h, "%d:%s;", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Key identifies a transcript: the same content transcribed with the same settings
+type Key struct {
+	Hash     string `json:"hash"` // hex SHA-256 of the hashed media
+	Model    string `json:"model"`
+	Language string `json:"language"`
+	Prompt   string `json:"prompt"`
+}
+
+// Entry is a cached transcript and the job that produced it
+type Entry struct {
+	Key       Key       `json:"key"`
+	SRTPath   string    `json:"-"`                // cached copy of the transcript
+	Source    string    `json:"source"`           // video the transcript was made from
+	Output    string    `json:"output,omitempty"` // final output of that job, once it finished
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Cache stores transcripts by content hash so identical media is transcribed only once
+type Cache interface {
+	// Lookup returns the entry for key, or ErrNotFound
+	Lookup(ctx context.Context, key Key) (*Entry, error)
+	// Store copies the SRT at srtPath into the cache under key
+	Store(ctx context.Context, key Key, srtPath, source string) error
+	// SetOutput records the final output of the job that produced key's transcript
+	SetOutput(ctx context.Context, key Key, output string) error
+}
@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+type implCache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates a file-backed Cache that keeps <id>.srt and <id>.json per key in dir
+func New(dir string) (Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &implCache{dir: dir}, nil
+}
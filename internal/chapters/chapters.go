@@ -0,0 +1,104 @@
+package chapters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Chapter is a titled section of a video, lasting until the next chapter starts
+type Chapter struct {
+	Start time.Duration
+	Title string
+}
+
+// Normalize sorts chapters, drops untitled ones and those starting at or after duration
+// (when known) or less than minLength after the previous chapter, and moves the first
+// chapter to 0 as video players and YouTube expect
+func Normalize(chapters []Chapter, duration, minLength time.Duration) []Chapter {
+	sorted := make([]Chapter, len(chapters))
+	copy(sorted, chapters)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var out []Chapter
+	for _, c := range sorted {
+		c.Title = strings.Join(strings.Fields(c.Title), " ")
+		if c.Title == "" || c.Start < 0 || (duration > 0 && c.Start >= duration) {
+			continue
+		}
+		if len(out) == 0 {
+			c.Start = 0
+		} else if c.Start-out[len(out)-1].Start < minLength {
+			continue
+		}
+		out = append(out, c)
+	}
+	// The last chapter must be long enough too
+	if n := len(out); n > 1 && duration > 0 && duration-out[n-1].Start < minLength {
+		out = out[:n-1]
+	}
+	return out
+}
+
+// FormatYouTube renders chapters as lines for a YouTube description, e.g. "05:12 Install".
+// Every timestamp gets an hour field when the video runs an hour or longer.
+func FormatYouTube(chapters []Chapter) string {
+	hours := len(chapters) > 0 && chapters[len(chapters)-1].Start >= time.Hour
+	var sb strings.Builder
+	for _, c := range chapters {
+		s := int(c.Start / time.Second)
+		if hours {
+			fmt.Fprintf(&sb, "%d:%02d:%02d %s\n", s/3600, s/60%60, s%60, c.Title)
+		} else {
+			fmt.Fprintf(&sb, "%02d:%02d %s\n", s/60, s%60, c.Title)
+		}
+	}
+	return sb.String()
+}
+
+// FormatWebVTT renders chapters as a WebVTT chapters track; each chapter ends where the
+// next begins and the last at duration
+func FormatWebVTT(chapters []Chapter, duration time.Duration) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n")
+	for i, c := range chapters {
+		fmt.Fprintf(&sb, "\n%d\n%s --> %s\n%s\n", i+1, vttTimestamp(c.Start), vttTimestamp(chapterEnd(chapters, i, duration)), c.Title)
+	}
+	return sb.String()
+}
+
+// FormatFFMetadata renders chapters as an ffmpeg metadata file for -map_chapters
+func FormatFFMetadata(chapters []Chapter, duration time.Duration) string {
+	var sb strings.Builder
+	sb.WriteString(";FFMETADATA1\n")
+	for i, c := range chapters {
+		fmt.Fprintf(&sb, "\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			c.Start.Milliseconds(), chapterEnd(chapters, i, duration).Milliseconds(), escapeMetadata(c.Title))
+	}
+	return sb.String()
+}
+
+// chapterEnd is the start of the next chapter, or duration for the last one. Without a
+// known duration the last chapter gets a nominal minute.
+func chapterEnd(chapters []Chapter, i int, duration time.Duration) time.Duration {
+	if i+1 < len(chapters) {
+		return chapters[i+1].Start
+	}
+	if duration > chapters[i].Start {
+		return duration
+	}
+	return chapters[i].Start + time.Minute
+}
+
+func vttTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// metadataEscaper backslash-escapes the characters special to ffmetadata values
+var metadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+
+func escapeMetadata(s string) string {
+	return metadataEscaper.Replace(s)
+}
@@ -0,0 +1,195 @@
+package chapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// fakeClient answers with the queued replies in order and records the prompts
+type fakeClient struct {
+	replies []string
+	prompts []string
+}
+
+func (f *fakeClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return f.GenerateJSON(ctx, prompt, nil)
+}
+
+func (f *fakeClient) GenerateJSON(_ context.Context, prompt string, _ map[string]any) (string, error) {
+	f.prompts = append(f.prompts, prompt)
+	reply := f.replies[0]
+	if len(f.replies) > 1 {
+		f.replies = f.replies[1:]
+	}
+	return reply, nil
+}
+
+// testPrompt loads the shipped chapters prompt
+func testPrompt(t *testing.T) *Prompt {
+	t.Helper()
+	prompt, err := LoadPrompt("../../prompts")
+	if err != nil {
+		t.Fatalf("LoadPrompt() error = %v", err)
+	}
+	return prompt
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []Chapter
+		duration time.Duration
+		want     []Chapter
+	}{
+		{
+			name:     "sorted and first moved to zero",
+			in:       []Chapter{{2 * time.Minute, "Deploy"}, {5 * time.Second, " Intro "}},
+			duration: 5 * time.Minute,
+			want:     []Chapter{{0, "Intro"}, {2 * time.Minute, "Deploy"}},
+		},
+		{
+			name:     "too close to previous",
+			in:       []Chapter{{0, "Intro"}, {10 * time.Second, "Setup"}, {time.Minute, "Build"}},
+			duration: 5 * time.Minute,
+			want:     []Chapter{{0, "Intro"}, {time.Minute, "Build"}},
+		},
+		{
+			name:     "untitled and past the end",
+			in:       []Chapter{{0, "Intro"}, {time.Minute, " "}, {10 * time.Minute, "Outro"}},
+			duration: 5 * time.Minute,
+			want:     []Chapter{{0, "Intro"}},
+		},
+		{
+			name:     "short last chapter",
+			in:       []Chapter{{0, "Intro"}, {2 * time.Minute, "Build"}, {4*time.Minute + 50*time.Second, "Bye"}},
+			duration: 5 * time.Minute,
+			want:     []Chapter{{0, "Intro"}, {2 * time.Minute, "Build"}},
+		},
+		{
+			name: "unknown duration",
+			in:   []Chapter{{0, "Intro"}, {time.Hour, "Later"}},
+			want: []Chapter{{0, "Intro"}, {time.Hour, "Later"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in, tt.duration, 30*time.Second); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatYouTube(t *testing.T) {
+	short := []Chapter{{0, "Intro"}, {5*time.Minute + 12*time.Second, "Install"}}
+	if got, want := FormatYouTube(short), "00:00 Intro\n05:12 Install\n"; got != want {
+		t.Errorf("FormatYouTube() = %q, want %q", got, want)
+	}
+	long := []Chapter{{0, "Intro"}, {time.Hour + 2*time.Minute + 3*time.Second, "Q&A"}}
+	if got, want := FormatYouTube(long), "0:00:00 Intro\n1:02:03 Q&A\n"; got != want {
+		t.Errorf("FormatYouTube() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatWebVTT(t *testing.T) {
+	chapters := []Chapter{{0, "Intro"}, {90 * time.Second, "Build"}}
+	want := "WEBVTT\n\n1\n00:00:00.000 --> 00:01:30.000\nIntro\n\n2\n00:01:30.000 --> 00:03:00.500\nBuild\n"
+	if got := FormatWebVTT(chapters, 3*time.Minute+500*time.Millisecond); got != want {
+		t.Errorf("FormatWebVTT() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFFMetadata(t *testing.T) {
+	chapters := []Chapter{{0, "Intro; a=b #1"}, {90 * time.Second, "Build"}}
+	want := ";FFMETADATA1\n" +
+		"\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=90000\ntitle=Intro\\; a\\=b \\#1\n" +
+		"\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=90000\nEND=150000\ntitle=Build\n"
+	if got := FormatFFMetadata(chapters, 0); got != want {
+		t.Errorf("FormatFFMetadata() = %q, want %q", got, want)
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"00:01:05", 65 * time.Second, false},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"12:30", 12*time.Minute + 30*time.Second, false},
+		{"00:00:07.500", 7 * time.Second, false},
+		{"90", 0, true},
+		{"00:75", 0, true},
+		{"ab:cd", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseClock(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseClock(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	cues := []subtitle.Cue{
+		{Index: 1, Start: 0, End: 4 * time.Second, Text: "Hello and welcome"},
+		{Index: 2, Start: 10 * time.Second, End: 14 * time.Second, Text: "today we deploy"},
+		{Index: 3, Start: 70 * time.Second, End: 75 * time.Second, Text: "first build\nthe image"},
+		{Index: 4, Start: 170 * time.Second, End: 180 * time.Second, Text: "then push it"},
+	}
+	client := &fakeClient{replies: []string{
+		"not json",
+		`{"chapters": [{"start": "00:00:02", "title": "Intro"}, {"start": "00:01:10", "title": "Build the image"}, {"start": "bad", "title": "x"}]}`,
+		`{"chapters": [{"start": "00:00:02", "title": "Intro"}, {"start": "00:01:10", "title": "Build the image"}]}`,
+	}}
+	cfg := config.ChaptersConfig{Enabled: true, MinLength: 30 * time.Second, MaxChapters: 5}
+	gen := New(client, testPrompt(t), cfg, logger.New("error"))
+
+	got, err := gen.Generate(context.Background(), cues, 0)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want := []Chapter{{0, "Intro"}, {70 * time.Second, "Build the image"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Generate() = %v, want %v", got, want)
+	}
+	if len(client.prompts) != 3 {
+		t.Fatalf("prompts = %d, want 3 (two rejected replies)", len(client.prompts))
+	}
+	for _, line := range []string{"[00:00:00] Hello and welcome today we deploy", "[00:01:10] first build the image", "[00:02:50] then push it", "At most 5 chapters, each at least 30s long"} {
+		if !strings.Contains(client.prompts[0], line) {
+			t.Errorf("prompt missing %q:\n%s", line, client.prompts[0])
+		}
+	}
+}
+
+func TestGenerateGivesUp(t *testing.T) {
+	client := &fakeClient{replies: []string{`{"chapters": []}`}}
+	gen := New(client, testPrompt(t), config.ChaptersConfig{MinLength: 30 * time.Second, MaxChapters: 5}, logger.New("error"))
+	if _, err := gen.Generate(context.Background(), nil, time.Minute); err == nil {
+		t.Fatal("Generate() error = nil, want an error after empty replies")
+	}
+	if len(client.prompts) != maxAttempts {
+		t.Errorf("prompts = %d, want %d", len(client.prompts), maxAttempts)
+	}
+}
+
+func TestLoadPromptChecksVariables(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, promptFile), []byte("{{.Transcrpt}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPrompt(dir); err == nil {
+		t.Error("LoadPrompt() should reject an unknown variable")
+	}
+}
@@ -0,0 +1,161 @@
+package chapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// maxAttempts is how often chapters are requested again when the reply does not parse
+const maxAttempts = 3
+
+// passageLength is how much speech is grouped under one timestamp in the prompt; chapter
+// starts fall on passage boundaries
+const passageLength = 20 * time.Second
+
+// chaptersSchema is the JSON schema of the reply
+var chaptersSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"chapters": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"start": map[string]any{"type": "string"},
+					"title": map[string]any{"type": "string"},
+				},
+				"required":             []string{"start", "title"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"chapters"},
+	"additionalProperties": false,
+}
+
+// reply is the JSON the model returns
+type reply struct {
+	Chapters []struct {
+		Start string `json:"start"`
+		Title string `json:"title"`
+	} `json:"chapters"`
+}
+
+// Generate sends the timestamped transcript and normalizes the chapters in the reply
+func (g *implGenerator) Generate(ctx context.Context, cues []subtitle.Cue, duration time.Duration) ([]Chapter, error) {
+	if duration <= 0 && len(cues) > 0 {
+		duration = cues[len(cues)-1].End
+	}
+	prompt, err := g.prompt.render(PromptData{
+		MaxChapters: g.cfg.MaxChapters,
+		MinLength:   g.cfg.MinLength.String(),
+		Transcript:  timestamped(cues),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		text, err := g.client.GenerateJSON(ctx, prompt, chaptersSchema)
+		if err != nil {
+			return nil, err
+		}
+
+		chapters, err := parseReply(text)
+		if err == nil {
+			chapters = Normalize(chapters, duration, g.cfg.MinLength)
+			if len(chapters) > 0 {
+				return chapters, nil
+			}
+			err = fmt.Errorf("no usable chapters in reply")
+		}
+		lastErr = err
+		g.logger.Warn(ctx, "Chapters reply rejected (attempt %d/%d): %v", attempt, maxAttempts, err)
+	}
+	return nil, lastErr
+}
+
+// timestamped groups cues into passages of about passageLength, one line each,
+// prefixed with the passage start
+func timestamped(cues []subtitle.Cue) string {
+	var sb strings.Builder
+	var passage []string
+	var start time.Duration
+	flush := func() {
+		if len(passage) > 0 {
+			fmt.Fprintf(&sb, "[%s] %s\n", clock(start), strings.Join(passage, " "))
+			passage = passage[:0]
+		}
+	}
+	for _, c := range cues {
+		text := strings.Join(c.Lines(), " ")
+		if text == "" {
+			continue
+		}
+		if len(passage) > 0 && c.Start-start >= passageLength {
+			flush()
+		}
+		if len(passage) == 0 {
+			start = c.Start
+		}
+		passage = append(passage, text)
+	}
+	flush()
+	return sb.String()
+}
+
+// parseReply decodes the JSON object in text into chapters
+func parseReply(text string) ([]Chapter, error) {
+	first, last := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("reply contains no JSON object")
+	}
+	var r reply
+	if err := json.Unmarshal([]byte(text[first:last+1]), &r); err != nil {
+		return nil, fmt.Errorf("decode reply: %w", err)
+	}
+
+	chapters := make([]Chapter, 0, len(r.Chapters))
+	for i, c := range r.Chapters {
+		start, err := parseClock(c.Start)
+		if err != nil {
+			return nil, fmt.Errorf("chapter %d: %w", i+1, err)
+		}
+		chapters = append(chapters, Chapter{Start: start, Title: c.Title})
+	}
+	return chapters, nil
+}
+
+// clock renders d as HH:MM:SS
+func clock(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
+
+// parseClock reads HH:MM:SS, H:MM:SS or MM:SS, ignoring any fraction of a second
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, ".,"); i >= 0 {
+		s = s[:i]
+	}
+	fields := strings.Split(s, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid start %q", s)
+	}
+	var d time.Duration
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid start %q", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return d * time.Second, nil
+}
@@ -0,0 +1,15 @@
+package chapters
+
+import (
+	"context"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// Generator derives chapters from a transcript with an LLM
+type Generator interface {
+	// Generate returns the chapters of a video of duration transcribed as cues, in time
+	// order, the first starting at 0
+	Generate(ctx context.Context, cues []subtitle.Cue, duration time.Duration) ([]Chapter, error)
+}
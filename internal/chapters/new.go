@@ -0,0 +1,24 @@
+package chapters
+
+import (
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/llm"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+)
+
+type implGenerator struct {
+	client llm.Client
+	prompt *Prompt
+	cfg    config.ChaptersConfig
+	logger logger.Logger
+}
+
+// New creates a Generator that renders prompt and sends it through client
+func New(client llm.Client, prompt *Prompt, cfg config.ChaptersConfig, log logger.Logger) Generator {
+	return &implGenerator{
+		client: client,
+		prompt: prompt,
+		cfg:    cfg,
+		logger: log,
+	}
+}
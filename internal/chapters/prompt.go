@@ -0,0 +1,52 @@
+package chapters
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// promptFile is the chapters prompt template below the prompts directory
+const promptFile = "chapters.tmpl"
+
+// PromptData is the data the chapters prompt is rendered with
+type PromptData struct {
+	MaxChapters int
+	MinLength   string // e.g. "30s"
+	Transcript  string // passages prefixed with their start as [HH:MM:SS]
+}
+
+// Prompt is the parsed chapters prompt template
+type Prompt struct {
+	tmpl *template.Template
+}
+
+// LoadPrompt parses chapters.tmpl from dir and renders it once with empty data, so a
+// misspelt variable fails at startup rather than mid-run
+func LoadPrompt(dir string) (*Prompt, error) {
+	path := filepath.Join(dir, promptFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read prompt: %w", err)
+	}
+	tmpl, err := template.New(promptFile).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", path, err)
+	}
+	if err := tmpl.Execute(io.Discard, PromptData{}); err != nil {
+		return nil, fmt.Errorf("check prompt %s: %w", path, err)
+	}
+	return &Prompt{tmpl: tmpl}, nil
+}
+
+// render executes the template with data
+func (p *Prompt) render(data PromptData) (string, error) {
+	var sb strings.Builder
+	if err := p.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", p.tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
@@ -1,6 +1,12 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
 
 type Config struct {
 	Whisper     WhisperConfig     `yaml:"whisper"`
@@ -9,15 +15,59 @@ type Config struct {
 	Logging     LoggingConfig     `yaml:"logging"`
 	Performance PerformanceConfig `yaml:"performance"`
 	Gemini      GeminiConfig      `yaml:"gemini"`
+	LLM         LLMConfig         `yaml:"llm"`
+	Subtitles   SubtitlesConfig   `yaml:"subtitles"`
+	// SubtitleStyle is the look of burned-in captions; routes can override it
+	SubtitleStyle SubtitleStyleConfig `yaml:"subtitle_style"`
+	Translation   TranslationConfig   `yaml:"translation"`
+	Chapters      ChaptersConfig      `yaml:"chapters"`
+	Executor      ExecutorConfig      `yaml:"executor"`
+	Cache         CacheConfig         `yaml:"cache"`
+	Filter        FilterConfig        `yaml:"filter"`
+	Watcher       WatcherConfig       `yaml:"watcher"`
+	Routes        []RouteConfig       `yaml:"routes"`
+	Server        ServerConfig        `yaml:"server"`
+	Metrics       MetricsConfig       `yaml:"metrics"`
 }
 
 type WhisperConfig struct {
+	// Backend selects the transcription engine: cli (whisper.cpp binary),
+	// server (whisper.cpp server) or openai (OpenAI-compatible API)
+	Backend    string `yaml:"backend"`
 	ModelPath  string `yaml:"model_path"`
 	BinaryPath string `yaml:"binary_path"`
+	ServerURL  string `yaml:"server_url"`
+	APIURL     string `yaml:"api_url"`
+	APIModel   string `yaml:"api_model"`
+	APIKeyEnv  string `yaml:"api_key_env"`
 	Language   string `yaml:"language"`
 	Prompt     string `yaml:"prompt"`
 	Threads    int    `yaml:"threads"`
 	UseGPU     bool   `yaml:"use_gpu"`
+	// Glossary is a YAML or CSV file of canonical terms; they are added to the prompt
+	// and misrecognitions are corrected after transcription
+	Glossary string `yaml:"glossary"`
+	// Chunking splits long recordings on silence and transcribes the chunks in parallel
+	Chunking ChunkingConfig `yaml:"chunking"`
+}
+
+// OpenAIMaxAudio is the longest 16 kHz mono WAV that fits the 25 MB upload limit of the
+// openai backend (about 13.6 minutes); longer recordings are always chunked for it
+const OpenAIMaxAudio = 13 * time.Minute
+
+type ChunkingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MinDuration is the media length from which recordings are split (default 30m)
+	MinDuration time.Duration `yaml:"min_duration"`
+	// ChunkDuration is the longest chunk; cuts go into the longest silence of its second half (default 10m)
+	ChunkDuration time.Duration `yaml:"chunk_duration"`
+	// Parallel chunks are transcribed at once, sharing whisper.threads between them (default 2)
+	Parallel int `yaml:"parallel"`
+	// SilenceThreshold and SilenceDuration tune ffmpeg silencedetect (default -35dB, 500ms)
+	SilenceThreshold string        `yaml:"silence_threshold"`
+	SilenceDuration  time.Duration `yaml:"silence_duration"`
+	// Overlap is the audio each chunk shares with its neighbours, so words at a cut are not lost (default 2s)
+	Overlap time.Duration `yaml:"overlap"`
 }
 
 type FFmpegConfig struct {
@@ -32,6 +82,8 @@ type PathsConfig struct {
 	Output   string `yaml:"output"`
 	Archived string `yaml:"archived"`
 	Temp     string `yaml:"temp"`
+	Jobs     string `yaml:"jobs"`
+	Cache    string `yaml:"cache"`
 }
 
 type LoggingConfig struct {
@@ -39,20 +91,220 @@ type LoggingConfig struct {
 	Format string `yaml:"format"`
 }
 
+// FilterConfig drops whisper hallucinations from transcripts
+type FilterConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxRepeats is the longest run of identical consecutive cues that is kept as is;
+	// longer runs are collapsed to their first cue (default 2)
+	MaxRepeats int `yaml:"max_repeats"`
+	// Phrases are dropped when they make up a whole cue, ignoring case and punctuation
+	// (default DefaultHallucinationPhrases)
+	Phrases []string `yaml:"phrases"`
+	// DropSilent drops cues that lie at least SilenceCoverage (default 0.9) within silences
+	// of SilenceDuration (default 2s) below SilenceThreshold (default -40dB)
+	DropSilent       bool          `yaml:"drop_silent"`
+	SilenceThreshold string        `yaml:"silence_threshold"`
+	SilenceDuration  time.Duration `yaml:"silence_duration"`
+	SilenceCoverage  float64       `yaml:"silence_coverage"`
+}
+
+// DefaultHallucinationPhrases are sign-offs whisper tends to invent over silence and music
+var DefaultHallucinationPhrases = []string{
+	"Thank you for watching",
+	"Thanks for watching",
+	"Please subscribe",
+	"Subscribe to my channel",
+	"Like and subscribe",
+	"Subtitles by the Amara.org community",
+}
+
+// CacheConfig controls reuse of transcripts for identical media
+type CacheConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Hash selects what is hashed: video (the input file) or audio (the extracted WAV)
+	Hash string `yaml:"hash"`
+	// SkipDuplicates finishes a job without output when the same content was
+	// already processed and that job's output still exists
+	SkipDuplicates bool `yaml:"skip_duplicates"`
+}
+
 type PerformanceConfig struct {
 	MaxConcurrent int `yaml:"max_concurrent"`
 }
 
 type GeminiConfig struct {
 	Model string `yaml:"model"`
+	// BaseURL overrides the Gemini API endpoint, e.g. for a proxy or a local stand-in
+	BaseURL string `yaml:"base_url"`
+	// MaxInputTokens is the estimated transcript size summarized in one request; longer
+	// transcripts are summarized in parts of this size and the notes merged afterwards
+	MaxInputTokens int `yaml:"max_input_tokens"`
+	// Prompts is the directory of prompt templates (styles/<name>.tmpl, part.tmpl, merge.tmpl, chapters.tmpl)
+	Prompts string `yaml:"prompts"`
+	// Style is the summary style used when -style is not given
+	Style string `yaml:"style"`
+	// Language is the output language named in the prompts
+	Language string `yaml:"language"`
+	// Headings are the summary section titles added around the LLM's content, in Language
+	Headings SummaryHeadingsConfig `yaml:"headings"`
+}
+
+// SummaryHeadingsConfig names the summary sections the renderers add themselves
+type SummaryHeadingsConfig struct {
+	KeyTerms string `yaml:"key_terms"`
+	Notes    string `yaml:"notes"`
+}
+
+// LLM providers
+const (
+	ProviderGemini = "gemini"
+	ProviderOpenAI = "openai" // any OpenAI-compatible chat completions API, e.g. vLLM
+	ProviderOllama = "ollama"
+)
+
+// LLMConfig selects the model behind summaries and translation
+type LLMConfig struct {
+	// Provider is gemini (model and base_url under gemini:), openai or ollama
+	Provider string `yaml:"provider"`
+	// KeysEnv names the environment variable holding comma-separated API keys, rotated on
+	// rate limits. Required for gemini; openai and ollama send no key when it is unset.
+	KeysEnv string         `yaml:"keys_env"`
+	OpenAI  ProviderConfig `yaml:"openai"`
+	Ollama  ProviderConfig `yaml:"ollama"`
+}
+
+type ProviderConfig struct {
+	Model   string `yaml:"model"`
+	BaseURL string `yaml:"base_url"`
+}
+
+type SubtitlesConfig struct {
+	// Formats lists extra subtitle formats written next to the SRT (vtt, ass, ttml, sbv, json)
+	Formats []string `yaml:"formats"`
+	// Mode chooses how captions end up in the video: burn (re-encode), mux (soft track, stream copy) or both
+	Mode string `yaml:"mode"`
+	// MKVCodec is the soft subtitle codec for Matroska outputs: srt or ass (styled with subtitle_style)
+	MKVCodec string `yaml:"mkv_codec"`
+	// Layout reflows the transcript for readability before it is translated, burned and exported
+	Layout LayoutConfig `yaml:"layout"`
+}
+
+type LayoutConfig struct {
+	Enabled         bool          `yaml:"enabled"`
+	MaxCharsPerLine int           `yaml:"max_chars_per_line"` // default 42
+	MaxLines        int           `yaml:"max_lines"`          // lines per cue, default 2
+	MaxDuration     time.Duration `yaml:"max_duration"`       // longer cues are split, default 7s
+	MinDuration     time.Duration `yaml:"min_duration"`       // minimum display time, default 1s
+	MinGap          time.Duration `yaml:"min_gap"`            // pause between cues, default 80ms
+	MergeBelow      time.Duration `yaml:"merge_below"`        // shorter cues are merged into a neighbour, default 700ms
+	Balance         bool          `yaml:"balance"`            // even out line lengths
+}
+
+// Layout converts the config into subtitle layout limits
+func (c LayoutConfig) Layout() subtitle.Layout {
+	return subtitle.Layout{
+		MaxCharsPerLine: c.MaxCharsPerLine,
+		MaxLines:        c.MaxLines,
+		MaxDuration:     c.MaxDuration,
+		MinDuration:     c.MinDuration,
+		MinGap:          c.MinGap,
+		MergeBelow:      c.MergeBelow,
+		Balance:         c.Balance,
+	}
+}
+
+// Subtitle output modes
+const (
+	ModeBurn = "burn"
+	ModeMux  = "mux"
+	ModeBoth = "both"
+)
+
+// ValidMode reports whether mode is a supported subtitles.mode
+func ValidMode(mode string) bool {
+	return mode == ModeBurn || mode == ModeMux || mode == ModeBoth
+}
+
+type ExecutorConfig struct {
+	// Timeout is the base limit per external command; 0 disables timeouts
+	Timeout time.Duration `yaml:"timeout"`
+	// TimeoutFactor adds this multiple of the media duration to Timeout for media commands
+	TimeoutFactor float64 `yaml:"timeout_factor"`
+	// KillGrace is the delay between SIGTERM and SIGKILL of a timed out command's process group
+	KillGrace time.Duration `yaml:"kill_grace"`
+	// Nice raises the niceness of ffmpeg/whisper so the machine stays responsive
+	Nice int `yaml:"nice"`
+	// MaxCPUTime limits the CPU time per process (RLIMIT_CPU)
+	MaxCPUTime time.Duration `yaml:"max_cpu_time"`
+	// MaxMemoryMB limits the address space per process (RLIMIT_AS, not enforced on macOS)
+	MaxMemoryMB int64 `yaml:"max_memory_mb"`
+}
+
+type TranslationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Language is the target language code, also used in the <name>.<lang>.srt file name
+	Language string `yaml:"language"`
+	// BatchSize is the number of cues sent to the LLM per request
+	BatchSize int `yaml:"batch_size"`
+	// Burn selects the track burned into the video: original, translation or bilingual
+	Burn string `yaml:"burn"`
+}
+
+type ChaptersConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MinLength is the shortest chapter kept; closer chapter starts are dropped (default 30s)
+	MinLength time.Duration `yaml:"min_length"`
+	// MaxChapters caps the number of chapters asked for (default 20)
+	MaxChapters int `yaml:"max_chapters"`
+}
+
+type WatcherConfig struct {
+	// StableWindow is how long size and mtime must stay unchanged before a file is dispatched
+	StableWindow time.Duration `yaml:"stable_window"`
+	// PollInterval is how often pending files are re-checked
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// ExclusiveOpen additionally requires an exclusive lock on the file to succeed
+	ExclusiveOpen bool `yaml:"exclusive_open"`
+}
+
+type ServerConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+	Path    string `yaml:"path"`
 }
 
 func (c *Config) Validate() error {
-	if c.Whisper.ModelPath == "" {
-		return fmt.Errorf("whisper.model_path is required")
+	if c.Whisper.Backend == "" {
+		c.Whisper.Backend = "cli"
 	}
-	if c.Whisper.BinaryPath == "" {
-		return fmt.Errorf("whisper.binary_path is required")
+	switch c.Whisper.Backend {
+	case "cli":
+		if c.Whisper.ModelPath == "" {
+			return fmt.Errorf("whisper.model_path is required")
+		}
+		if c.Whisper.BinaryPath == "" {
+			return fmt.Errorf("whisper.binary_path is required")
+		}
+	case "server":
+		if c.Whisper.ServerURL == "" {
+			return fmt.Errorf("whisper.server_url is required for the server backend")
+		}
+	case "openai":
+		if c.Whisper.APIURL == "" {
+			c.Whisper.APIURL = "https://api.openai.com/v1"
+		}
+		if c.Whisper.APIModel == "" {
+			c.Whisper.APIModel = "whisper-1"
+		}
+		if c.Whisper.APIKeyEnv == "" {
+			c.Whisper.APIKeyEnv = "OPENAI_API_KEY"
+		}
+	default:
+		return fmt.Errorf("whisper.backend %q is not supported (cli, server, openai)", c.Whisper.Backend)
 	}
 	if c.Whisper.Language == "" {
 		return fmt.Errorf("whisper.language is required")
@@ -73,18 +325,294 @@ func (c *Config) Validate() error {
 	if c.Paths.Temp == "" {
 		c.Paths.Temp = "data/temp"
 	}
+	if c.Paths.Jobs == "" {
+		c.Paths.Jobs = "data/jobs"
+	}
+	if c.Paths.Cache == "" {
+		c.Paths.Cache = "data/cache"
+	}
 	if c.Performance.MaxConcurrent == 0 {
 		c.Performance.MaxConcurrent = 2
 	}
 	if c.Whisper.Threads == 0 {
 		c.Whisper.Threads = 8
 	}
+	if err := c.Whisper.Chunking.validate(); err != nil {
+		return err
+	}
+	if chunking := c.Whisper.Chunking; c.Whisper.Backend == "openai" && chunking.ChunkDuration+2*chunking.Overlap > OpenAIMaxAudio {
+		return fmt.Errorf("whisper.chunking.chunk_duration plus twice the overlap must be at most %s for the openai backend", OpenAIMaxAudio)
+	}
 	if c.FFmpeg.Preset == "" {
 		c.FFmpeg.Preset = "medium"
 	}
 	if c.Gemini.Model == "" {
 		c.Gemini.Model = "gemini-2.5-flash"
 	}
+	if c.Gemini.MaxInputTokens == 0 {
+		c.Gemini.MaxInputTokens = 100000
+	}
+	if c.Gemini.Prompts == "" {
+		c.Gemini.Prompts = "prompts"
+	}
+	if c.Gemini.Style == "" {
+		c.Gemini.Style = "detailed"
+	}
+	if c.Gemini.Language == "" {
+		c.Gemini.Language = "tiếng Việt"
+		// Headings follow the default language only when that is the one in use
+		if c.Gemini.Headings == (SummaryHeadingsConfig{}) {
+			c.Gemini.Headings = SummaryHeadingsConfig{KeyTerms: "Thuật ngữ chính", Notes: "Lưu ý quan trọng"}
+		}
+	}
+	if c.Gemini.Headings.KeyTerms == "" {
+		c.Gemini.Headings.KeyTerms = "Key terms"
+	}
+	if c.Gemini.Headings.Notes == "" {
+		c.Gemini.Headings.Notes = "Important notes"
+	}
+	if err := c.LLM.validate(); err != nil {
+		return err
+	}
+	switch c.Logging.Format {
+	case "":
+		c.Logging.Format = "text"
+	case "text", "json":
+	default:
+		return fmt.Errorf("logging.format %q is not supported (text, json)", c.Logging.Format)
+	}
+	if c.Server.Addr == "" {
+		c.Server.Addr = "127.0.0.1:8080"
+	}
+	if c.Metrics.Addr == "" {
+		c.Metrics.Addr = "127.0.0.1:9090"
+	}
+	if c.Metrics.Path == "" {
+		c.Metrics.Path = "/metrics"
+	}
+	if err := c.Subtitles.Layout.validate(); err != nil {
+		return err
+	}
+	if c.Executor.KillGrace == 0 {
+		c.Executor.KillGrace = 10 * time.Second
+	}
+	if c.Executor.Timeout < 0 || c.Executor.TimeoutFactor < 0 || c.Executor.MaxCPUTime < 0 || c.Executor.MaxMemoryMB < 0 {
+		return fmt.Errorf("executor limits must not be negative")
+	}
+	if c.Executor.Nice < -20 || c.Executor.Nice > 19 {
+		return fmt.Errorf("executor.nice must be between -20 and 19")
+	}
+	if err := c.Filter.validate(); err != nil {
+		return err
+	}
+	if c.Chapters.MinLength == 0 {
+		c.Chapters.MinLength = 30 * time.Second
+	}
+	if c.Chapters.MaxChapters == 0 {
+		c.Chapters.MaxChapters = 20
+	}
+	if c.Chapters.MinLength < 10*time.Second || c.Chapters.MaxChapters < 2 {
+		return fmt.Errorf("chapters.min_length must be at least 10s and chapters.max_chapters at least 2")
+	}
+	switch c.Cache.Hash {
+	case "":
+		c.Cache.Hash = "video"
+	case "video", "audio":
+	default:
+		return fmt.Errorf("cache.hash %q is not supported (video, audio)", c.Cache.Hash)
+	}
+	if c.Watcher.StableWindow < 0 || c.Watcher.PollInterval < 0 {
+		return fmt.Errorf("watcher.stable_window and watcher.poll_interval must not be negative")
+	}
+	if c.Watcher.StableWindow == 0 {
+		c.Watcher.StableWindow = 5 * time.Second
+	}
+	if c.Watcher.PollInterval == 0 {
+		c.Watcher.PollInterval = time.Second
+	}
+	for i, route := range c.Routes {
+		if route.Match == "" {
+			return fmt.Errorf("routes[%d].match is required", i)
+		}
+		if _, err := path.Match(route.Match, ""); err != nil {
+			return fmt.Errorf("routes[%d].match %q: %w", i, route.Match, err)
+		}
+		if route.Burn != nil {
+			return fmt.Errorf("routes[%d].burn is no longer supported, use mode (burn: false is mode: mux)", i)
+		}
+		if route.Mode != "" && !ValidMode(route.Mode) {
+			return fmt.Errorf("routes[%d].mode %q is not supported (burn, mux, both)", i, route.Mode)
+		}
+		if _, err := c.SubtitleStyle.Merge(route.Style).ASSStyle(); err != nil {
+			return fmt.Errorf("routes[%d].style: %w", i, err)
+		}
+	}
+	if c.Translation.Burn == "" {
+		c.Translation.Burn = "original"
+	}
+	if c.Translation.BatchSize == 0 {
+		c.Translation.BatchSize = 50
+	}
+	if c.Translation.Enabled {
+		if c.Translation.Language == "" {
+			return fmt.Errorf("translation.language is required when translation is enabled")
+		}
+		switch c.Translation.Burn {
+		case "original", "translation", "bilingual":
+		default:
+			return fmt.Errorf("translation.burn %q is not supported (original, translation, bilingual)", c.Translation.Burn)
+		}
+	}
+	if _, err := c.SubtitleStyle.ASSStyle(); err != nil {
+		return fmt.Errorf("subtitle_style: %w", err)
+	}
+	if c.Subtitles.Mode == "" {
+		c.Subtitles.Mode = ModeBurn
+	}
+	if !ValidMode(c.Subtitles.Mode) {
+		return fmt.Errorf("subtitles.mode %q is not supported (burn, mux, both)", c.Subtitles.Mode)
+	}
+	switch c.Subtitles.MKVCodec {
+	case "":
+		c.Subtitles.MKVCodec = "srt"
+	case "srt", "ass":
+	default:
+		return fmt.Errorf("subtitles.mkv_codec %q is not supported (srt, ass)", c.Subtitles.MKVCodec)
+	}
+	for _, name := range c.Subtitles.Formats {
+		if _, err := subtitle.ParseFormat(name); err != nil {
+			return fmt.Errorf("subtitles.formats: %w", err)
+		}
+	}
 
 	return nil
 }
+
+// validate fills in chunking defaults and checks that chunks are longer than their overlap
+func (c *ChunkingConfig) validate() error {
+	if c.MinDuration == 0 {
+		c.MinDuration = 30 * time.Minute
+	}
+	if c.ChunkDuration == 0 {
+		c.ChunkDuration = 10 * time.Minute
+	}
+	if c.Parallel == 0 {
+		c.Parallel = 2
+	}
+	if c.SilenceThreshold == "" {
+		c.SilenceThreshold = "-35dB"
+	}
+	if c.SilenceDuration == 0 {
+		c.SilenceDuration = 500 * time.Millisecond
+	}
+	if c.Overlap == 0 {
+		c.Overlap = 2 * time.Second
+	}
+	if c.Parallel < 1 {
+		return fmt.Errorf("whisper.chunking.parallel must be at least 1")
+	}
+	if c.Overlap < 0 || c.ChunkDuration < 4*c.Overlap {
+		return fmt.Errorf("whisper.chunking.chunk_duration must be at least 4 times the overlap")
+	}
+	return nil
+}
+
+// validate fills in layout defaults and rejects negative limits
+func (c *LayoutConfig) validate() error {
+	if c.MaxCharsPerLine == 0 {
+		c.MaxCharsPerLine = 42
+	}
+	if c.MaxLines == 0 {
+		c.MaxLines = 2
+	}
+	if c.MaxDuration == 0 {
+		c.MaxDuration = 7 * time.Second
+	}
+	if c.MinDuration == 0 {
+		c.MinDuration = time.Second
+	}
+	if c.MinGap == 0 {
+		c.MinGap = 80 * time.Millisecond
+	}
+	if c.MergeBelow == 0 {
+		c.MergeBelow = 700 * time.Millisecond
+	}
+	if c.MaxCharsPerLine < 0 || c.MaxLines < 0 || c.MaxDuration < 0 || c.MinDuration < 0 || c.MinGap < 0 || c.MergeBelow < 0 {
+		return fmt.Errorf("subtitles.layout limits must not be negative")
+	}
+	if c.MinDuration > c.MaxDuration {
+		return fmt.Errorf("subtitles.layout.min_duration must not exceed max_duration")
+	}
+	return nil
+}
+
+// validate fills in filter defaults
+func (c *FilterConfig) validate() error {
+	if c.MaxRepeats == 0 {
+		c.MaxRepeats = 2
+	}
+	if c.Phrases == nil {
+		c.Phrases = DefaultHallucinationPhrases
+	}
+	if c.SilenceThreshold == "" {
+		c.SilenceThreshold = "-40dB"
+	}
+	if c.SilenceDuration == 0 {
+		c.SilenceDuration = 2 * time.Second
+	}
+	if c.SilenceCoverage == 0 {
+		c.SilenceCoverage = 0.9
+	}
+	if c.MaxRepeats < 1 {
+		return fmt.Errorf("filter.max_repeats must be at least 1")
+	}
+	if c.SilenceDuration < 0 || c.SilenceCoverage < 0 || c.SilenceCoverage > 1 {
+		return fmt.Errorf("filter.silence_duration must not be negative and filter.silence_coverage must be between 0 and 1")
+	}
+	return nil
+}
+
+// LLMModel returns the model of the selected LLM provider
+func (c *Config) LLMModel() string {
+	switch c.LLM.Provider {
+	case ProviderOpenAI:
+		return c.LLM.OpenAI.Model
+	case ProviderOllama:
+		return c.LLM.Ollama.Model
+	default:
+		return c.Gemini.Model
+	}
+}
+
+// validate fills in the provider defaults
+func (c *LLMConfig) validate() error {
+	switch c.Provider {
+	case "":
+		c.Provider = ProviderGemini
+		fallthrough
+	case ProviderGemini:
+		if c.KeysEnv == "" {
+			c.KeysEnv = "GEMINI_API_KEYS"
+		}
+	case ProviderOpenAI:
+		if c.KeysEnv == "" {
+			c.KeysEnv = "OPENAI_API_KEYS"
+		}
+		if c.OpenAI.BaseURL == "" {
+			c.OpenAI.BaseURL = "https://api.openai.com/v1"
+		}
+		if c.OpenAI.Model == "" {
+			c.OpenAI.Model = "gpt-4o-mini"
+		}
+	case ProviderOllama:
+		if c.Ollama.BaseURL == "" {
+			c.Ollama.BaseURL = "http://localhost:11434"
+		}
+		if c.Ollama.Model == "" {
+			return fmt.Errorf("llm.ollama.model is required when llm.provider is ollama")
+		}
+	default:
+		return fmt.Errorf("llm.provider %q is not supported (gemini, openai, ollama)", c.Provider)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestValidate(t *testing.T) {
@@ -61,6 +62,232 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "server backend without model path",
+			config: Config{
+				Whisper: WhisperConfig{
+					Backend:   "server",
+					ServerURL: "http://127.0.0.1:8080",
+					Language:  "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "openai chunks over the upload limit",
+			config: Config{
+				Whisper: WhisperConfig{
+					Backend:  "openai",
+					Language: "en",
+					Chunking: ChunkingConfig{ChunkDuration: 20 * time.Minute},
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown whisper backend",
+			config: Config{
+				Whisper: WhisperConfig{
+					Backend:  "vosk",
+					Language: "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown subtitle format",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Subtitles: SubtitlesConfig{
+					Formats: []string{"vtt", "docx"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown route mode",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Routes: []RouteConfig{{Match: "clients/*", Mode: "soft"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "route burn flag",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Routes: []RouteConfig{{Match: "clients/*", Burn: new(bool)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "nice out of range",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Executor: ExecutorConfig{Nice: 25},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown llm provider",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				LLM: LLMConfig{Provider: "mistral"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ollama without model",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				LLM: LLMConfig{Provider: ProviderOllama},
+			},
+			wantErr: true,
+		},
+		{
+			name: "chapters too short",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Chapters: ChaptersConfig{Enabled: true, MinLength: 5 * time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative stable window",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Watcher: WatcherConfig{StableWindow: -time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative poll interval",
+			config: Config{
+				Whisper: WhisperConfig{
+					ModelPath:  "models/test.bin",
+					BinaryPath: "./whisper",
+					Language:   "en",
+				},
+				FFmpeg: FFmpegConfig{
+					Encoder: "h264_videotoolbox",
+				},
+				Paths: PathsConfig{
+					Input:  "data/input",
+					Output: "data/output",
+				},
+				Watcher: WatcherConfig{PollInterval: -time.Second},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -100,6 +327,9 @@ paths:
 logging:
   level: "info"
   format: "text"
+
+watcher:
+  stable_window: "10s"
 `
 
 	if _, err := tmpfile.Write([]byte(content)); err != nil {
@@ -122,6 +352,14 @@ logging:
 	if cfg.Paths.Input != "data/input" {
 		t.Errorf("Input = %v, want %v", cfg.Paths.Input, "data/input")
 	}
+
+	if cfg.Watcher.StableWindow != 10*time.Second {
+		t.Errorf("StableWindow = %v, want %v", cfg.Watcher.StableWindow, 10*time.Second)
+	}
+
+	if cfg.Watcher.PollInterval != time.Second {
+		t.Errorf("PollInterval = %v, want default %v", cfg.Watcher.PollInterval, time.Second)
+	}
 }
 
 func TestLoadInvalidFile(t *testing.T) {
@@ -130,3 +368,143 @@ func TestLoadInvalidFile(t *testing.T) {
 		t.Error("Load() should return error for nonexistent file")
 	}
 }
+
+func TestMatchRoute(t *testing.T) {
+	cfg := Config{
+		Routes: []RouteConfig{
+			{Match: "clients/acme", Output: "acme", Mode: ModeMux},
+			{Match: "courses/*", Language: "vi"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		relDir    string
+		wantMatch string
+		wantOK    bool
+	}{
+		{"root folder", "", "", false},
+		{"exact folder", "clients/acme", "clients/acme", true},
+		{"nested below match", "clients/acme/2024/q1", "clients/acme", true},
+		{"glob segment", "courses/golang", "courses/*", true},
+		{"glob nested", "courses/golang/week1", "courses/*", true},
+		{"windows separators", `courses\golang`, "courses/*", true},
+		{"no match", "misc", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, ok := cfg.MatchRoute(tt.relDir)
+			if ok != tt.wantOK {
+				t.Fatalf("MatchRoute() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if route.Match != tt.wantMatch {
+				t.Errorf("MatchRoute() = %v, want %v", route.Match, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestSubtitleStyle(t *testing.T) {
+	yes := true
+	outline := 2.5
+	base := SubtitleStyleConfig{FontFamily: "Roboto", FontSize: 20, PrimaryColor: "#FFCC00", Outline: &outline}
+	merged := base.Merge(SubtitleStyleConfig{FontSize: 24, Alignment: "top", BackgroundBox: &yes})
+
+	style, err := merged.ASSStyle()
+	if err != nil {
+		t.Fatalf("ASSStyle() error = %v", err)
+	}
+	if style.FontName != "Roboto" || style.FontSize != 24 || style.Outline != 2.5 {
+		t.Errorf("merge lost fields: %+v", style)
+	}
+	if style.PrimaryColour != "&H0000CCFF" {
+		t.Errorf("PrimaryColour = %q", style.PrimaryColour)
+	}
+	if style.Alignment != 8 {
+		t.Errorf("Alignment = %d, want 8", style.Alignment)
+	}
+	if style.BorderStyle != 3 || style.OutlineColour != "&H7F000000" {
+		t.Errorf("background box = border %d outline %q, want 3 and the default box color", style.BorderStyle, style.OutlineColour)
+	}
+
+	cfg := Config{
+		Whisper:       WhisperConfig{ModelPath: "m.bin", BinaryPath: "./whisper", Language: "en"},
+		FFmpeg:        FFmpegConfig{Encoder: "libx264"},
+		Paths:         PathsConfig{Input: "in", Output: "out"},
+		SubtitleStyle: base,
+		Routes:        []RouteConfig{{Match: "acme", Style: SubtitleStyleConfig{BackColor: "navy"}}},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an invalid route style color")
+	}
+}
+
+func TestSubtitleStyleNegative(t *testing.T) {
+	negFloat, negInt := -1.0, -10
+	zeroFloat, zeroInt := 0.0, 0
+
+	tests := []struct {
+		name    string
+		style   SubtitleStyleConfig
+		wantErr bool
+	}{
+		{"zero sizes and margins", SubtitleStyleConfig{Outline: &zeroFloat, Shadow: &zeroFloat, MarginL: &zeroInt, MarginR: &zeroInt, MarginV: &zeroInt}, false},
+		{"negative outline", SubtitleStyleConfig{Outline: &negFloat}, true},
+		{"negative shadow", SubtitleStyleConfig{Shadow: &negFloat}, true},
+		{"negative margin_l", SubtitleStyleConfig{MarginL: &negInt}, true},
+		{"negative margin_r", SubtitleStyleConfig{MarginR: &negInt}, true},
+		{"negative margin_v", SubtitleStyleConfig{MarginV: &negInt}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.style.ASSStyle(); (err != nil) != tt.wantErr {
+				t.Errorf("ASSStyle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// Routes go through the same check
+			cfg := Config{
+				Whisper: WhisperConfig{ModelPath: "m.bin", BinaryPath: "./whisper", Language: "en"},
+				FFmpeg:  FFmpegConfig{Encoder: "libx264"},
+				Paths:   PathsConfig{Input: "in", Output: "out"},
+				Routes:  []RouteConfig{{Match: "acme", Style: tt.style}},
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() with route style error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSummaryHeadings(t *testing.T) {
+	base := Config{
+		Whisper: WhisperConfig{ModelPath: "m.bin", BinaryPath: "./whisper", Language: "en"},
+		FFmpeg:  FFmpegConfig{Encoder: "libx264"},
+		Paths:   PathsConfig{Input: "in", Output: "out"},
+	}
+
+	tests := []struct {
+		name     string
+		gemini   GeminiConfig
+		wantTerm string
+		wantNote string
+	}{
+		{"default language", GeminiConfig{}, "Thuật ngữ chính", "Lưu ý quan trọng"},
+		{"other language", GeminiConfig{Language: "English"}, "Key terms", "Important notes"},
+		{"configured", GeminiConfig{Language: "Deutsch", Headings: SummaryHeadingsConfig{KeyTerms: "Begriffe", Notes: "Hinweise"}}, "Begriffe", "Hinweise"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.Gemini = tt.gemini
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := cfg.Gemini.Headings; got.KeyTerms != tt.wantTerm || got.Notes != tt.wantNote {
+				t.Errorf("Headings = %+v, want %q and %q", got, tt.wantTerm, tt.wantNote)
+			}
+		})
+	}
+}
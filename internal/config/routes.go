@@ -0,0 +1,41 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// RouteConfig holds per-subfolder overrides for videos dropped below paths.input
+type RouteConfig struct {
+	// Match is a slash-separated glob (path.Match syntax) of the subfolder relative to paths.input.
+	// A route also applies to everything nested below a matching folder.
+	Match    string `yaml:"match"`
+	Language string `yaml:"language"`
+	Prompt   string `yaml:"prompt"`
+	// Output is the output subfolder; when empty the input subfolder is mirrored
+	Output string `yaml:"output"`
+	// Mode overrides subtitles.mode (burn, mux or both)
+	Mode string `yaml:"mode"`
+	// Burn is no longer supported and only kept so Validate can point old configs at mode
+	Burn *bool `yaml:"burn"`
+	// Style overrides individual subtitle_style fields, e.g. for a client's brand guidelines
+	Style SubtitleStyleConfig `yaml:"style"`
+}
+
+// MatchRoute returns the first route matching relDir or any of its parent folders
+func (c *Config) MatchRoute(relDir string) (RouteConfig, bool) {
+	relDir = strings.Trim(path.Clean("/"+strings.ReplaceAll(relDir, "\\", "/")), "/")
+	if relDir == "" {
+		return RouteConfig{}, false
+	}
+
+	for _, route := range c.Routes {
+		pattern := strings.Trim(route.Match, "/")
+		for dir := relDir; dir != "." && dir != ""; dir = path.Dir(dir) {
+			if ok, _ := path.Match(pattern, dir); ok {
+				return route, true
+			}
+		}
+	}
+	return RouteConfig{}, false
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// SubtitleStyleConfig controls how burned-in captions look. Unset fields keep the
+// libass defaults; a route's or a job's style only overrides the fields it sets.
+type SubtitleStyleConfig struct {
+	FontFamily string  `yaml:"font_family" json:"font_family,omitempty"`
+	FontSize   float64 `yaml:"font_size" json:"font_size,omitempty"`
+	// Colors are "#RRGGBB" or "#RRGGBBAA" (AA = opacity)
+	PrimaryColor string `yaml:"primary_color" json:"primary_color,omitempty"`
+	OutlineColor string `yaml:"outline_color" json:"outline_color,omitempty"`
+	// BackColor is the shadow color, or the box color when BackgroundBox is set
+	BackColor string   `yaml:"back_color" json:"back_color,omitempty"`
+	Outline   *float64 `yaml:"outline" json:"outline,omitempty"`
+	Shadow    *float64 `yaml:"shadow" json:"shadow,omitempty"`
+	Bold      *bool    `yaml:"bold" json:"bold,omitempty"`
+	MarginL   *int     `yaml:"margin_l" json:"margin_l,omitempty"`
+	MarginR   *int     `yaml:"margin_r" json:"margin_r,omitempty"`
+	MarginV   *int     `yaml:"margin_v" json:"margin_v,omitempty"`
+	// Alignment is a numpad position (1-9) or bottom, top, middle-left, ...
+	Alignment string `yaml:"alignment" json:"alignment,omitempty"`
+	// BackgroundBox draws an opaque box in BackColor behind each line instead of an outline
+	BackgroundBox *bool `yaml:"background_box" json:"background_box,omitempty"`
+}
+
+// defaultBoxColor is a half-transparent black box
+const defaultBoxColor = "#00000080"
+
+// Merge returns s with every field set in override replacing its counterpart
+func (s SubtitleStyleConfig) Merge(override SubtitleStyleConfig) SubtitleStyleConfig {
+	if override.FontFamily != "" {
+		s.FontFamily = override.FontFamily
+	}
+	if override.FontSize != 0 {
+		s.FontSize = override.FontSize
+	}
+	if override.PrimaryColor != "" {
+		s.PrimaryColor = override.PrimaryColor
+	}
+	if override.OutlineColor != "" {
+		s.OutlineColor = override.OutlineColor
+	}
+	if override.BackColor != "" {
+		s.BackColor = override.BackColor
+	}
+	if override.Outline != nil {
+		s.Outline = override.Outline
+	}
+	if override.Shadow != nil {
+		s.Shadow = override.Shadow
+	}
+	if override.Bold != nil {
+		s.Bold = override.Bold
+	}
+	if override.MarginL != nil {
+		s.MarginL = override.MarginL
+	}
+	if override.MarginR != nil {
+		s.MarginR = override.MarginR
+	}
+	if override.MarginV != nil {
+		s.MarginV = override.MarginV
+	}
+	if override.Alignment != "" {
+		s.Alignment = override.Alignment
+	}
+	if override.BackgroundBox != nil {
+		s.BackgroundBox = override.BackgroundBox
+	}
+	return s
+}
+
+// ASSStyle converts the config into the ASS style used for burning
+func (s SubtitleStyleConfig) ASSStyle() (subtitle.Style, error) {
+	style := subtitle.DefaultStyle()
+	if s.FontFamily != "" {
+		style.FontName = s.FontFamily
+	}
+	if s.FontSize < 0 {
+		return style, fmt.Errorf("font_size must be positive")
+	}
+	if s.FontSize > 0 {
+		style.FontSize = s.FontSize
+	}
+
+	colors := []struct {
+		name  string
+		value string
+		dst   *string
+	}{
+		{"primary_color", s.PrimaryColor, &style.PrimaryColour},
+		{"outline_color", s.OutlineColor, &style.OutlineColour},
+		{"back_color", s.BackColor, &style.BackColour},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		color, err := subtitle.ParseColor(c.value)
+		if err != nil {
+			return style, fmt.Errorf("%s: %w", c.name, err)
+		}
+		*c.dst = color
+	}
+
+	sizes := []struct {
+		name  string
+		value *float64
+		dst   *float64
+	}{
+		{"outline", s.Outline, &style.Outline},
+		{"shadow", s.Shadow, &style.Shadow},
+	}
+	for _, sz := range sizes {
+		if sz.value == nil {
+			continue
+		}
+		if *sz.value < 0 {
+			return style, fmt.Errorf("%s must not be negative", sz.name)
+		}
+		*sz.dst = *sz.value
+	}
+	if s.Bold != nil {
+		style.Bold = *s.Bold
+	}
+
+	margins := []struct {
+		name  string
+		value *int
+		dst   *int
+	}{
+		{"margin_l", s.MarginL, &style.MarginL},
+		{"margin_r", s.MarginR, &style.MarginR},
+		{"margin_v", s.MarginV, &style.MarginV},
+	}
+	for _, m := range margins {
+		if m.value == nil {
+			continue
+		}
+		if *m.value < 0 {
+			return style, fmt.Errorf("%s must not be negative", m.name)
+		}
+		*m.dst = *m.value
+	}
+	if s.Alignment != "" {
+		alignment, err := subtitle.ParseAlignment(s.Alignment)
+		if err != nil {
+			return style, fmt.Errorf("alignment: %w", err)
+		}
+		style.Alignment = alignment
+	}
+
+	// libass paints the opaque box in the outline colour
+	if s.BackgroundBox != nil && *s.BackgroundBox {
+		style.BorderStyle = subtitle.BorderOpaqueBox
+		if s.BackColor == "" {
+			style.BackColour, _ = subtitle.ParseColor(defaultBoxColor)
+		}
+		style.OutlineColour = style.BackColour
+	}
+	return style, nil
+}
@@ -0,0 +1,80 @@
+package glossary
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxPromptChars keeps the whisper prompt within the model's prompt window (about 224 tokens)
+const maxPromptChars = 800
+
+// Apply replaces misrecognitions, pattern matches and wrongly cased terms in text with the
+// canonical terms. Only whole words match: a match must not touch a letter or digit.
+func (g *Glossary) Apply(text string) (string, []Correction) {
+	if g == nil {
+		return text, nil
+	}
+
+	var corrections []Correction
+	for _, r := range g.rules {
+		var sb strings.Builder
+		last := 0
+		for _, m := range r.re.FindAllStringIndex(text, -1) {
+			from := text[m[0]:m[1]]
+			if m[0] == m[1] || from == r.term || !wholeWord(text, m[0], m[1]) {
+				continue
+			}
+			sb.WriteString(text[last:m[0]])
+			sb.WriteString(r.term)
+			last = m[1]
+			corrections = append(corrections, Correction{From: from, To: r.term})
+		}
+		if last > 0 {
+			sb.WriteString(text[last:])
+			text = sb.String()
+		}
+	}
+	return text, corrections
+}
+
+// Prompt appends the glossary's terms that base does not mention yet, so whisper is
+// primed with the right spellings. Terms that would overflow the prompt window are left out.
+func (g *Glossary) Prompt(base string) string {
+	if g == nil {
+		return base
+	}
+
+	prompt := strings.TrimSpace(base)
+	lower := strings.ToLower(prompt)
+	for _, t := range g.Terms {
+		if strings.Contains(lower, strings.ToLower(t.Term)) {
+			continue
+		}
+		next := t.Term
+		if prompt != "" {
+			next = prompt + ", " + t.Term
+		}
+		if utf8.RuneCountInString(next) > maxPromptChars {
+			break
+		}
+		prompt = next
+		lower = strings.ToLower(prompt)
+	}
+	return prompt
+}
+
+// wholeWord reports whether text[start:end] is not glued to a letter or digit on either side
+func wholeWord(text string, start, end int) bool {
+	if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(r) {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(r) {
+		return false
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
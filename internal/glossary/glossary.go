@@ -0,0 +1,34 @@
+package glossary
+
+import "regexp"
+
+// Term is a canonical spelling together with the ways speech recognition gets it wrong
+type Term struct {
+	Term string `yaml:"term"`
+	// Misrecognitions are literal phrases replaced by Term; spaces match any whitespace
+	Misrecognitions []string `yaml:"misrecognitions"`
+	// Patterns are regular expressions replaced by Term
+	Patterns []string `yaml:"patterns"`
+	// MatchCase limits misrecognitions and patterns to their exact case,
+	// for terms that clash with ordinary words ("Go", "IT")
+	MatchCase bool `yaml:"match_case"`
+}
+
+// Glossary corrects transcripts to canonical terms and lists them for the whisper prompt.
+// A nil *Glossary is valid and changes nothing.
+type Glossary struct {
+	Terms []Term `yaml:"terms"`
+	rules []rule
+}
+
+// Correction is one replacement made by Apply
+type Correction struct {
+	From string
+	To   string
+}
+
+// rule replaces every whole-word match of re with term
+type rule struct {
+	term string
+	re   *regexp.Regexp
+}
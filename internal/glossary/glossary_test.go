@@ -0,0 +1,106 @@
+package glossary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	g, err := New([]Term{
+		{Term: "Kubernetes", Misrecognitions: []string{"cube nets", "cooper netties"}, Patterns: []string{`k8s?`}},
+		{Term: "kubectl", Misrecognitions: []string{"cube control", "cube cuddle"}},
+		{Term: "Go", Misrecognitions: []string{"Goal Lang"}, MatchCase: true},
+		{Term: "GitHub"},
+		{Term: "IT", MatchCase: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in, want    string
+		corrections int
+	}{
+		{"Deploy it to Cube Nets with cube\ncontrol.", "Deploy it to Kubernetes with kubectl.", 2},
+		{"We use kubernetes and k8s.", "We use Kubernetes and Kubernetes.", 2},
+		{"Push it to github, then to GitHub.", "Push it to GitHub, then to GitHub.", 1},
+		// Whole words only: no match inside other words
+		{"The cube networks and k8sctl stay.", "The cube networks and k8sctl stay.", 0},
+		// match_case keeps ordinary lower-case words
+		{"Written in Goal Lang, the goal lang goal.", "Written in Go, the goal lang goal.", 1},
+		{"Put it there and let's go home.", "Put it there and let's go home.", 0},
+		{"Already Kubernetes.", "Already Kubernetes.", 0},
+	}
+
+	for _, tt := range tests {
+		got, corrections := g.Apply(tt.in)
+		if got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if len(corrections) != tt.corrections {
+			t.Errorf("Apply(%q) made %d corrections %v, want %d", tt.in, len(corrections), corrections, tt.corrections)
+		}
+	}
+
+	var none *Glossary
+	if got, _ := none.Apply("cube nets"); got != "cube nets" {
+		t.Errorf("nil Apply() = %q", got)
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	g, err := New([]Term{{Term: "Kubernetes"}, {Term: "Helm"}, {Term: "kubectl"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := g.Prompt("Kubernetes, containers"), "Kubernetes, containers, Helm, kubectl"; got != want {
+		t.Errorf("Prompt() = %q, want %q", got, want)
+	}
+	if got, want := g.Prompt(""), "Kubernetes, Helm, kubectl"; got != want {
+		t.Errorf("Prompt(\"\") = %q, want %q", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "glossary.yaml")
+	csvPath := filepath.Join(dir, "glossary.csv")
+	if err := os.WriteFile(yamlPath, []byte(`terms:
+  - term: Kubernetes
+    misrecognitions: ["cube nets"]
+    patterns: ["k8s?"]
+  - term: Helm
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(csvPath, []byte(`term,misrecognitions
+# comment
+Kubernetes,cube nets,re:k8s?
+Helm
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{yamlPath, csvPath} {
+		g, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", filepath.Base(path), err)
+		}
+		if len(g.Terms) != 2 || g.Terms[0].Misrecognitions[0] != "cube nets" || g.Terms[0].Patterns[0] != "k8s?" {
+			t.Errorf("Load(%s) = %+v", filepath.Base(path), g.Terms)
+		}
+		if got, _ := g.Apply("helm on k8s"); got != "Helm on Kubernetes" {
+			t.Errorf("Load(%s).Apply() = %q", filepath.Base(path), got)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("terms:\n  - term: X\n    patterns: [\"(\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bad); err == nil {
+		t.Error("Load() with an invalid pattern: want error")
+	}
+}
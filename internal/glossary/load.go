@@ -0,0 +1,112 @@
+package glossary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads a glossary from a YAML file (a "terms" list of Term) or a CSV file with
+// one term per row: the canonical term followed by its misrecognitions, where cells
+// starting with "re:" are patterns. A header row starting with "term" is skipped.
+func Load(path string) (*Glossary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read glossary: %w", err)
+	}
+
+	var terms []Term
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var g Glossary
+		if err := yaml.Unmarshal(data, &g); err != nil {
+			return nil, fmt.Errorf("parse glossary: %w", err)
+		}
+		terms = g.Terms
+	case ".csv":
+		if terms, err = parseCSV(string(data)); err != nil {
+			return nil, fmt.Errorf("parse glossary: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("glossary %s: unsupported format (yaml, csv)", path)
+	}
+
+	return New(terms)
+}
+
+// New builds a glossary from terms, compiling their misrecognitions and patterns
+func New(terms []Term) (*Glossary, error) {
+	g := &Glossary{Terms: terms}
+	for i, t := range terms {
+		t.Term = strings.TrimSpace(t.Term)
+		if t.Term == "" {
+			return nil, fmt.Errorf("glossary term %d is empty", i+1)
+		}
+		g.Terms[i] = t
+
+		// The term itself is matched case-insensitively to fix its casing, unless it
+		// must match case: then other casings are ordinary words ("go", "it")
+		flags := "(?i)"
+		if t.MatchCase {
+			flags = ""
+		} else {
+			g.rules = append(g.rules, rule{term: t.Term, re: regexp.MustCompile(flags + literal(t.Term))})
+		}
+		for _, m := range t.Misrecognitions {
+			if m = strings.TrimSpace(m); m != "" {
+				g.rules = append(g.rules, rule{term: t.Term, re: regexp.MustCompile(flags + literal(m))})
+			}
+		}
+		for _, p := range t.Patterns {
+			re, err := regexp.Compile(flags + "(?:" + p + ")")
+			if err != nil {
+				return nil, fmt.Errorf("glossary term %q: pattern %q: %w", t.Term, p, err)
+			}
+			g.rules = append(g.rules, rule{term: t.Term, re: re})
+		}
+	}
+	return g, nil
+}
+
+// literal quotes s for a regexp, letting its spaces match any run of whitespace
+func literal(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	return strings.Join(words, `\s+`)
+}
+
+func parseCSV(data string) ([]Term, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []Term
+	for i, row := range rows {
+		if len(row) == 0 || (i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "term")) {
+			continue
+		}
+		t := Term{Term: row[0]}
+		for _, cell := range row[1:] {
+			if pattern, ok := strings.CutPrefix(cell, "re:"); ok {
+				t.Patterns = append(t.Patterns, pattern)
+			} else if cell = strings.TrimSpace(cell); cell != "" {
+				t.Misrecognitions = append(t.Misrecognitions, cell)
+			}
+		}
+		terms = append(terms, t)
+	}
+	return terms, nil
+}
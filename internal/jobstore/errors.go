@@ -0,0 +1,6 @@
+package jobstore
+
+import "errors"
+
+// ErrJobNotFound is returned when no job is stored under the requested ID
+var ErrJobNotFound = errors.New("job not found")
@@ -0,0 +1,13 @@
+package jobstore
+
+import "context"
+
+// Store defines the interface for persisting pipeline jobs and their stage checkpoints
+type Store interface {
+	// Open returns the resumable job for videoPath. A fresh job is created when none
+	// exists, the previous one already completed, or the file changed since it was recorded.
+	Open(ctx context.Context, videoPath string) (*Job, error)
+	Get(ctx context.Context, id string) (*Job, error)
+	Save(ctx context.Context, job *Job) error
+	List(ctx context.Context) ([]*Job, error)
+}
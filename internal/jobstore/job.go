@@ -0,0 +1,70 @@
+package jobstore
+
+import "time"
+
+// Completed returns the checkpoint of stage if it finished successfully
+func (j *Job) Completed(stage Stage) (*StageRecord, bool) {
+	rec, ok := j.Stages[stage]
+	if !ok || rec.Status != StatusCompleted {
+		return nil, false
+	}
+	return rec, true
+}
+
+// StartStage marks stage (and the job) as running
+func (j *Job) StartStage(stage Stage) {
+	j.Stages[stage] = &StageRecord{
+		Status:    StatusRunning,
+		StartedAt: time.Now(),
+	}
+	j.Status = StatusRunning
+	j.Error = ""
+}
+
+// CompleteStage records a successful stage together with the files it produced
+func (j *Job) CompleteStage(stage Stage, artifacts map[string]string) {
+	rec := j.stage(stage)
+	rec.Status = StatusCompleted
+	rec.CompletedAt = time.Now()
+	rec.Error = ""
+	rec.Artifacts = artifacts
+}
+
+// FailStage records the error that stopped stage
+func (j *Job) FailStage(stage Stage, err error) {
+	rec := j.stage(stage)
+	rec.Status = StatusFailed
+	rec.Error = err.Error()
+}
+
+// Finish marks the whole job as completed or failed
+func (j *Job) Finish(err error) {
+	if err != nil {
+		j.Status = StatusFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = StatusCompleted
+	j.Error = ""
+}
+
+// Cancel marks the job as canceled by the user, failing the stage that was running
+func (j *Job) Cancel() {
+	for _, rec := range j.Stages {
+		if rec.Status == StatusRunning {
+			rec.Status = StatusFailed
+			rec.Error = "canceled"
+		}
+	}
+	j.Status = StatusCanceled
+	j.Error = "canceled"
+}
+
+func (j *Job) stage(stage Stage) *StageRecord {
+	rec, ok := j.Stages[stage]
+	if !ok {
+		rec = &StageRecord{StartedAt: time.Now()}
+		j.Stages[stage] = rec
+	}
+	return rec
+}
@@ -0,0 +1,20 @@
+package jobstore
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+type implStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates a file-backed Store that keeps one JSON document per job in dir
+func New(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create job store dir: %w", err)
+	}
+	return &implStore{dir: dir}, nil
+}
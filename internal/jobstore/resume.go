@@ -0,0 +1,37 @@
+package jobstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resumable lists the unfinished jobs in store whose video still exists below inputDir.
+// Nothing else picks them up after a restart: watch mode only reacts to new files and
+// the job API only to requests.
+func Resumable(ctx context.Context, store Store, inputDir string) ([]*Job, error) {
+	jobs, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	absInput, err := filepath.Abs(inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*Job
+	for _, job := range jobs {
+		if job.Status == StatusCompleted || job.Status == StatusCanceled {
+			continue
+		}
+		if rel, err := filepath.Rel(absInput, job.VideoPath); err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if _, err := os.Stat(job.VideoPath); err != nil {
+			continue
+		}
+		out = append(out, job)
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package jobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Open returns the job for videoPath, resuming the previous run when the same file is still unfinished
+func (s *implStore) Open(ctx context.Context, videoPath string) (*Job, error) {
+	absPath, err := filepath.Abs(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve video path: %w", err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat video: %w", err)
+	}
+
+	id := jobID(absPath)
+	job, err := s.Get(ctx, id)
+	if err != nil && !errors.Is(err, ErrJobNotFound) {
+		return nil, err
+	}
+
+	// Resume only when the very same file is still waiting to be finished
+	if job != nil && job.Status != StatusCompleted &&
+		job.Size == info.Size() && job.ModTime.Equal(info.ModTime()) {
+		return job, nil
+	}
+
+	now := time.Now()
+	job = &Job{
+		ID:        id,
+		VideoPath: absPath,
+		Size:      info.Size(),
+		ModTime:   info.ModTime(),
+		Status:    StatusPending,
+		Stages:    make(map[Stage]*StageRecord),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.Save(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get loads a job by ID
+func (s *implStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.path(id))
+}
+
+// Save writes the job atomically so a crash never leaves a half-written checkpoint
+func (s *implStore) Save(ctx context.Context, job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode job: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".job-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp job file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write job: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync job: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close job: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(job.ID)); err != nil {
+		return fmt.Errorf("commit job: %w", err)
+	}
+	return nil
+}
+
+// List returns all stored jobs, oldest first
+func (s *implStore) List(ctx context.Context) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read job store: %w", err)
+	}
+
+	var jobs []*Job
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		job, err := s.read(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+func (s *implStore) read(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("read job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("decode job %s: %w", filepath.Base(path), err)
+	}
+	if job.Stages == nil {
+		job.Stages = make(map[Stage]*StageRecord)
+	}
+	return &job, nil
+}
+
+func (s *implStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// IDFor returns the ID Open gives the job for videoPath, without touching the store
+func IDFor(videoPath string) (string, error) {
+	absPath, err := filepath.Abs(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve video path: %w", err)
+	}
+	return jobID(absPath), nil
+}
+
+// jobID derives a stable ID from the absolute video path so restarts find the same job
+func jobID(absPath string) string {
+	sum := sha256.Sum256([]byte(absPath))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,181 @@
+package jobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) (Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := New(filepath.Join(dir, "jobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	video := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(video, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return store, video
+}
+
+func TestOpenResumesUnfinishedJob(t *testing.T) {
+	ctx := context.Background()
+	store, video := newTestStore(t)
+
+	job, err := store.Open(ctx, video)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	job.StartStage(StageExtract)
+	job.CompleteStage(StageExtract, map[string]string{"audio": "a.wav"})
+	job.FailStage(StageTranscribe, fmt.Errorf("boom"))
+	job.Finish(fmt.Errorf("boom"))
+	if err := store.Save(ctx, job); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	resumed, err := store.Open(ctx, video)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if resumed.ID != job.ID {
+		t.Errorf("ID = %v, want %v", resumed.ID, job.ID)
+	}
+	rec, ok := resumed.Completed(StageExtract)
+	if !ok {
+		t.Fatal("extract checkpoint was not restored")
+	}
+	if rec.Artifacts["audio"] != "a.wav" {
+		t.Errorf("audio artifact = %v, want a.wav", rec.Artifacts["audio"])
+	}
+	if _, ok := resumed.Completed(StageTranscribe); ok {
+		t.Error("failed stage should not be reported as completed")
+	}
+}
+
+func TestOpenStartsFreshJob(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(t *testing.T, store Store, job *Job, video string)
+	}{
+		{
+			name: "previous job completed",
+			mutate: func(t *testing.T, store Store, job *Job, video string) {
+				job.Finish(nil)
+				if err := store.Save(context.Background(), job); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "file replaced",
+			mutate: func(t *testing.T, store Store, job *Job, video string) {
+				if err := os.WriteFile(video, []byte("another video"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				later := time.Now().Add(time.Minute)
+				if err := os.Chtimes(video, later, later); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, video := newTestStore(t)
+
+			job, err := store.Open(ctx, video)
+			if err != nil {
+				t.Fatal(err)
+			}
+			job.CompleteStage(StageExtract, nil)
+			if err := store.Save(ctx, job); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.mutate(t, store, job, video)
+
+			fresh, err := store.Open(ctx, video)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			if fresh.Status != StatusPending {
+				t.Errorf("Status = %v, want %v", fresh.Status, StatusPending)
+			}
+			if len(fresh.Stages) != 0 {
+				t.Errorf("Stages = %v, want none", fresh.Stages)
+			}
+		})
+	}
+}
+
+func TestGetAndList(t *testing.T) {
+	ctx := context.Background()
+	store, video := newTestStore(t)
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get() error = %v, want ErrJobNotFound", err)
+	}
+
+	job, err := store.Open(ctx, video)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Errorf("List() = %v, want [%s]", jobs, job.ID)
+	}
+}
+
+func TestResumable(t *testing.T) {
+	ctx := context.Background()
+	store, video := newTestStore(t)
+	dir := filepath.Dir(video)
+
+	running, err := store.Open(ctx, video)
+	if err != nil {
+		t.Fatal(err)
+	}
+	running.StartStage(StageExtract)
+
+	done := filepath.Join(dir, "done.mp4")
+	gone := filepath.Join(dir, "gone.mp4")
+	for _, path := range []string{done, gone} {
+		if err := os.WriteFile(path, []byte("video"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	finished, _ := store.Open(ctx, done)
+	finished.Finish(nil)
+	removed, _ := store.Open(ctx, gone)
+	os.Remove(gone)
+	for _, job := range []*Job{running, finished, removed} {
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, err := Resumable(ctx, store, dir)
+	if err != nil {
+		t.Fatalf("Resumable() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != running.ID {
+		t.Errorf("Resumable() = %d jobs, want only the running one", len(jobs))
+	}
+	if jobs, _ := Resumable(ctx, store, filepath.Join(dir, "other")); len(jobs) != 0 {
+		t.Errorf("Resumable() outside the input dir = %d jobs, want 0", len(jobs))
+	}
+}
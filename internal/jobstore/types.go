@@ -0,0 +1,68 @@
+package jobstore
+
+import (
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+)
+
+// Stage identifies a checkpointed step of the processing pipeline
+type Stage string
+
+const (
+	StageExtract    Stage = "extract"
+	StageTranscribe Stage = "transcribe"
+	StageFilter     Stage = "filter"
+	StageGlossary   Stage = "glossary"
+	StageLayout     Stage = "layout"
+	StageTranslate  Stage = "translate"
+	StageChapters   Stage = "chapters"
+	StageBurn       Stage = "burn"
+	StageMux        Stage = "mux"
+	StageExport     Stage = "export"
+	StageArchive    Stage = "archive"
+)
+
+// Stages lists the pipeline stages in execution order
+var Stages = []Stage{StageExtract, StageTranscribe, StageFilter, StageGlossary, StageLayout, StageTranslate, StageChapters, StageBurn, StageMux, StageExport, StageArchive}
+
+// Status describes the state of a job or of a single stage
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// StageRecord is the checkpoint of one stage, including the files it produced
+type StageRecord struct {
+	Status      Status            `json:"status"`
+	StartedAt   time.Time         `json:"started_at"`
+	CompletedAt time.Time         `json:"completed_at,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Artifacts   map[string]string `json:"artifacts,omitempty"`
+}
+
+// Job is the persisted state of one video going through the pipeline
+type Job struct {
+	ID          string                 `json:"id"`
+	VideoPath   string                 `json:"video_path"`
+	Size        int64                  `json:"size"`
+	ModTime     time.Time              `json:"mod_time"`
+	Duration    time.Duration          `json:"duration,omitempty"`     // media duration, probed once
+	ContentHash string                 `json:"content_hash,omitempty"` // transcript cache hash, computed once
+	DuplicateOf string                 `json:"duplicate_of,omitempty"` // earlier video this job was skipped as a duplicate of
+	Status      Status                 `json:"status"`
+	Error       string                 `json:"error,omitempty"`
+	Stages      map[Stage]*StageRecord `json:"stages"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+
+	// Mode and Style override subtitles.mode and the caption style for this job only;
+	// they are set on submission through the job API and kept for resumed runs
+	Mode  string                      `json:"mode,omitempty"`
+	Style *config.SubtitleStyleConfig `json:"style,omitempty"`
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// maxBackoff caps the exponential backoff between rate-limited attempts
+const maxBackoff = 60 * time.Second
+
+func (c *implClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return c.generate(ctx, prompt, nil)
+}
+
+func (c *implClient) GenerateJSON(ctx context.Context, prompt string, schema map[string]any) (string, error) {
+	return c.generate(ctx, prompt, schema)
+}
+
+// generate sends prompt to the provider and returns the response text.
+// Rotates API keys on 429 / quota errors, backing off exponentially.
+func (c *implClient) generate(ctx context.Context, prompt string, schema map[string]any) (string, error) {
+	name := c.provider.name()
+	attempts := max(len(c.apiKeys), 1) * 3 // Try each key multiple times with backoff
+	var lastErr error
+	backoff := c.backoff
+
+	for i := 0; i < attempts; i++ {
+		keyIndex, key := c.key()
+		if i > 0 {
+			c.metrics.LLMRetry(name)
+		}
+
+		text, err := c.provider.generate(ctx, key, prompt, schema)
+		if err != nil {
+			if isRateLimited(err) {
+				c.metrics.LLMCall(name, err, true)
+				c.logger.Warn(ctx, "%s key %d rate limited, rotating... (attempt %d/%d). Sleeping for %v", name, keyIndex+1, i+1, attempts, backoff)
+				c.rotateKey(keyIndex)
+				lastErr = err
+
+				select {
+				case <-ctx.Done():
+					return "", ctx.Err()
+				case <-time.After(backoff):
+				}
+				backoff *= 2 // Exponential backoff
+				if backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+				continue
+			}
+			c.metrics.LLMCall(name, err, false)
+			return "", err
+		}
+		c.metrics.LLMCall(name, nil, false)
+		return text, nil
+	}
+
+	return "", fmt.Errorf("all API keys exhausted: %w", lastErr)
+}
+
+// statusError is a non-2xx reply from an HTTP provider
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.code, e.body)
+}
+
+// isRateLimited reports whether err is an HTTP 429 from a provider, going by the status code
+// rather than the error text, which may quote any number
+func isRateLimited(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests
+	}
+	var ae genai.APIError
+	return errors.As(err, &ae) && ae.Code == http.StatusTooManyRequests
+}
+
+// key returns the key currently in use and its index; the key is empty when none are configured
+func (c *implClient) key() (int, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.apiKeys) == 0 {
+		return 0, ""
+	}
+	return c.currentKey, c.apiKeys[c.currentKey]
+}
+
+// rotateKey moves past the key at index unless another caller already rotated it
+func (c *implClient) rotateKey(index int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.apiKeys) > 0 && c.currentKey == index {
+		c.currentKey = (c.currentKey + 1) % len(c.apiKeys)
+	}
+}
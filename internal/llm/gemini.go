@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// geminiProvider calls the Gemini API through the genai SDK
+type geminiProvider struct {
+	model   string
+	baseURL string // overrides the Gemini API endpoint, e.g. for a proxy or a local stand-in
+}
+
+func (p *geminiProvider) name() string { return "gemini" }
+
+func (p *geminiProvider) generate(ctx context.Context, key, prompt string, schema map[string]any) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("no Gemini API keys configured")
+	}
+
+	clientCfg := &genai.ClientConfig{
+		APIKey:  key,
+		Backend: genai.BackendGeminiAPI,
+	}
+	if p.baseURL != "" {
+		clientCfg.HTTPOptions.BaseURL = p.baseURL
+	}
+	client, err := genai.NewClient(ctx, clientCfg)
+	if err != nil {
+		return "", fmt.Errorf("create client: %w", err)
+	}
+
+	var genCfg *genai.GenerateContentConfig
+	if schema != nil {
+		genCfg = &genai.GenerateContentConfig{ResponseMIMEType: "application/json", ResponseJsonSchema: schema}
+	}
+	result, err := client.Models.GenerateContent(ctx, p.model, genai.Text(prompt), genCfg)
+	if err != nil {
+		return "", fmt.Errorf("generate content: %w", err)
+	}
+	if result != nil && len(result.Candidates) > 0 && result.Candidates[0].Content != nil {
+		var text string
+		for _, part := range result.Candidates[0].Content.Parts {
+			if part.Text != "" {
+				text += part.Text
+			}
+		}
+		return text, nil
+	}
+	return "", fmt.Errorf("empty response from Gemini")
+}
@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+	"google.golang.org/genai"
+)
+
+func TestGenerateRotatesKeys(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models/test-model:generateContent") {
+			http.NotFound(w, r)
+			return
+		}
+		key := r.Header.Get("x-goog-api-key")
+		keys = append(keys, key)
+		if key == "limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"error": {"code": 429, "message": "quota exceeded", "status": "RESOURCE_EXHAUSTED"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "Hello "}, {"text": "world"}]}}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Gemini: config.GeminiConfig{Model: "test-model", BaseURL: srv.URL}}
+	client := New(cfg, []string{"limited", "good"}, metrics.NewNop(), logger.New("error")).(*implClient)
+	client.backoff = time.Millisecond
+
+	got, err := client.Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got != "Hello world" {
+		t.Errorf("Generate() = %q, want %q", got, "Hello world")
+	}
+	if strings.Join(keys, ",") != "limited,good" {
+		t.Errorf("keys used = %v, want the limited key then the good one", keys)
+	}
+}
+
+func TestGeminiRequiresKey(t *testing.T) {
+	client := New(&config.Config{}, nil, metrics.NewNop(), logger.New("error"))
+	if _, err := client.Generate(context.Background(), "hi"); err == nil || !strings.Contains(err.Error(), "no Gemini API keys") {
+		t.Errorf("Generate() error = %v, want missing key error", err)
+	}
+}
+
+func TestGeminiGenerateJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			GenerationConfig struct {
+				ResponseMIMEType   string         `json:"responseMimeType"`
+				ResponseJsonSchema map[string]any `json:"responseJsonSchema"`
+			} `json:"generationConfig"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		if req.GenerationConfig.ResponseMIMEType != "application/json" || req.GenerationConfig.ResponseJsonSchema["type"] != "object" {
+			t.Errorf("generationConfig = %+v, want JSON with the schema", req.GenerationConfig)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "{}"}]}}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Gemini: config.GeminiConfig{Model: "test-model", BaseURL: srv.URL}}
+	got, err := New(cfg, []string{"key"}, metrics.NewNop(), logger.New("error")).GenerateJSON(context.Background(), "hi", map[string]any{"type": "object"})
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+	if got != "{}" {
+		t.Errorf("GenerateJSON() = %q, want %q", got, "{}")
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"http 429", &statusError{code: http.StatusTooManyRequests}, true},
+		{"wrapped genai 429", fmt.Errorf("generate content: %w", genai.APIError{Code: http.StatusTooManyRequests, Status: "RESOURCE_EXHAUSTED"}), true},
+		{"http 400 quoting 429", &statusError{code: http.StatusBadRequest, body: "max_tokens 4290 exceeds quota"}, false},
+		{"genai 500", genai.APIError{Code: http.StatusInternalServerError, Message: "retry in 429ms"}, false},
+		{"plain error", errors.New("dial tcp: 429 quota"), false},
+	}
+	for _, tt := range tests {
+		if got := isRateLimited(tt.err); got != tt.want {
+			t.Errorf("%s: isRateLimited() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
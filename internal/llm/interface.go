@@ -0,0 +1,20 @@
+package llm
+
+import "context"
+
+// Client sends prompts to the configured LLM provider, rotating through API keys on
+// rate limits. It is safe for concurrent use.
+type Client interface {
+	// Generate returns the model's text reply to prompt
+	Generate(ctx context.Context, prompt string) (string, error)
+	// GenerateJSON returns the model's reply to prompt constrained to schema, a JSON Schema
+	// object. Providers differ in how strictly they follow it, so callers still validate.
+	GenerateJSON(ctx context.Context, prompt string, schema map[string]any) (string, error)
+}
+
+// provider makes a single request to one LLM API. key is empty when no keys are configured;
+// a nil schema asks for free text.
+type provider interface {
+	name() string
+	generate(ctx context.Context, key, prompt string, schema map[string]any) (string, error)
+}
@@ -0,0 +1,27 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeysFromEnv reads comma-separated API keys from the environment variable env
+func KeysFromEnv(env string) ([]string, error) {
+	keysEnv := os.Getenv(env)
+	if keysEnv == "" {
+		return nil, fmt.Errorf("%s environment variable is not set", env)
+	}
+
+	var keys []string
+	for _, k := range strings.Split(keysEnv, ",") {
+		k = strings.TrimSpace(k)
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no valid API keys found in %s", env)
+	}
+	return keys, nil
+}
@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+)
+
+type implClient struct {
+	provider provider
+	apiKeys  []string
+	metrics  metrics.Metrics
+	logger   logger.Logger
+
+	mu         sync.Mutex
+	currentKey int
+	backoff    time.Duration // first sleep after a rate limit, doubled per retry
+}
+
+// New returns a client for cfg.LLM.Provider. apiKeys may be empty for openai and ollama.
+func New(cfg *config.Config, apiKeys []string, m metrics.Metrics, log logger.Logger) Client {
+	var p provider
+	switch cfg.LLM.Provider {
+	case config.ProviderOpenAI:
+		p = &openAIProvider{model: cfg.LLM.OpenAI.Model, baseURL: cfg.LLM.OpenAI.BaseURL, http: http.DefaultClient}
+	case config.ProviderOllama:
+		p = &ollamaProvider{model: cfg.LLM.Ollama.Model, baseURL: cfg.LLM.Ollama.BaseURL, http: http.DefaultClient}
+	default:
+		model := cfg.Gemini.Model
+		if model == "" {
+			model = "gemini-2.5-flash"
+		}
+		p = &geminiProvider{model: model, baseURL: cfg.Gemini.BaseURL}
+	}
+	return &implClient{
+		provider: p,
+		apiKeys:  apiKeys,
+		metrics:  m,
+		logger:   log,
+		backoff:  5 * time.Second,
+	}
+}
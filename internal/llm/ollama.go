@@ -0,0 +1,44 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ollamaProvider calls the chat API of an Ollama server
+type ollamaProvider struct {
+	model   string
+	baseURL string // e.g. http://localhost:11434
+	http    *http.Client
+}
+
+type ollamaRequest struct {
+	Model    string         `json:"model"`
+	Messages []chatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Format   map[string]any `json:"format,omitempty"` // JSON schema of the reply
+}
+
+type ollamaResponse struct {
+	Message chatMessage `json:"message"`
+}
+
+func (p *ollamaProvider) name() string { return "ollama" }
+
+func (p *ollamaProvider) generate(ctx context.Context, key, prompt string, schema map[string]any) (string, error) {
+	req := ollamaRequest{
+		Model:    p.model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+		Format:   schema,
+	}
+	var resp ollamaResponse
+	if err := postJSON(ctx, p.http, strings.TrimSuffix(p.baseURL, "/")+"/api/chat", key, req, &resp); err != nil {
+		return "", fmt.Errorf("ollama chat: %w", err)
+	}
+	if resp.Message.Content == "" {
+		return "", fmt.Errorf("empty response from %s", p.model)
+	}
+	return resp.Message.Content, nil
+}
@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+)
+
+func TestOllamaGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization = %q, want none without keys", auth)
+		}
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		if req.Stream || req.Model != "llama3.1" || req.Format != nil {
+			t.Errorf("request = %+v, want non-streaming llama3.1 without format", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"model":   req.Model,
+			"message": map[string]string{"role": "assistant", "content": "echo: " + req.Messages[0].Content},
+			"done":    true,
+		})
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{LLM: config.LLMConfig{
+		Provider: config.ProviderOllama,
+		Ollama:   config.ProviderConfig{Model: "llama3.1", BaseURL: srv.URL},
+	}}
+	got, err := New(cfg, nil, metrics.NewNop(), logger.New("error")).Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got != "echo: hi" {
+		t.Errorf("Generate() = %q, want %q", got, "echo: hi")
+	}
+}
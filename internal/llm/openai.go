@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAIProvider calls an OpenAI-compatible chat completions API (OpenAI, vLLM, LiteLLM, ...)
+type openAIProvider struct {
+	model   string
+	baseURL string // up to and including the version, e.g. https://api.openai.com/v1
+	http    *http.Client
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []chatMessage         `json:"messages"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIResponseFormat asks for structured output matching a JSON schema
+type openAIResponseFormat struct {
+	Type       string `json:"type"` // json_schema
+	JSONSchema struct {
+		Name   string         `json:"name"`
+		Schema map[string]any `json:"schema"`
+		Strict bool           `json:"strict"`
+	} `json:"json_schema"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (p *openAIProvider) name() string { return "openai" }
+
+func (p *openAIProvider) generate(ctx context.Context, key, prompt string, schema map[string]any) (string, error) {
+	req := openAIRequest{
+		Model:    p.model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+	}
+	if schema != nil {
+		req.ResponseFormat = &openAIResponseFormat{Type: "json_schema"}
+		req.ResponseFormat.JSONSchema.Name = "response"
+		req.ResponseFormat.JSONSchema.Schema = schema
+		req.ResponseFormat.JSONSchema.Strict = true
+	}
+	var resp openAIResponse
+	if err := postJSON(ctx, p.http, strings.TrimSuffix(p.baseURL, "/")+"/chat/completions", key, req, &resp); err != nil {
+		return "", fmt.Errorf("chat completion: %w", err)
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("empty response from %s", p.model)
+	}
+	return resp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+)
+
+func TestOpenAIGenerate(t *testing.T) {
+	var auths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		auths = append(auths, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") == "Bearer limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"error": {"message": "Rate limit reached"}}`)
+			return
+		}
+
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		if req.Model != "qwen2.5" || len(req.Messages) != 1 || req.Messages[0].Role != "user" {
+			t.Errorf("request = %+v, want one user message for qwen2.5", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]string{"role": "assistant", "content": "echo: " + req.Messages[0].Content}}},
+		})
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{LLM: config.LLMConfig{
+		Provider: config.ProviderOpenAI,
+		OpenAI:   config.ProviderConfig{Model: "qwen2.5", BaseURL: srv.URL + "/v1/"},
+	}}
+	client := New(cfg, []string{"limited", "good"}, metrics.NewNop(), logger.New("error")).(*implClient)
+	client.backoff = time.Millisecond
+
+	got, err := client.Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got != "echo: hi" {
+		t.Errorf("Generate() = %q, want %q", got, "echo: hi")
+	}
+	if len(auths) != 2 || auths[0] != "Bearer limited" || auths[1] != "Bearer good" {
+		t.Errorf("Authorization headers = %q, want the limited key then the good one", auths)
+	}
+}
+
+func TestOpenAIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error": {"message": "model not found"}}`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{LLM: config.LLMConfig{
+		Provider: config.ProviderOpenAI,
+		OpenAI:   config.ProviderConfig{Model: "missing", BaseURL: srv.URL},
+	}}
+	_, err := New(cfg, nil, metrics.NewNop(), logger.New("error")).Generate(context.Background(), "hi")
+	if err == nil {
+		t.Fatal("Generate() error = nil, want the HTTP 400")
+	}
+	if isRateLimited(err) {
+		t.Errorf("isRateLimited(%v) = true, want false", err)
+	}
+}
+
+func TestOpenAIGenerateJSON(t *testing.T) {
+	schema := map[string]any{"type": "object", "properties": map[string]any{"title": map[string]any{"type": "string"}}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_schema" || req.ResponseFormat.JSONSchema.Schema["type"] != "object" {
+			t.Errorf("response_format = %+v, want the json_schema", req.ResponseFormat)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"choices": [{"message": {"role": "assistant", "content": "{\"title\": \"x\"}"}}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{LLM: config.LLMConfig{
+		Provider: config.ProviderOpenAI,
+		OpenAI:   config.ProviderConfig{Model: "gpt", BaseURL: srv.URL},
+	}}
+	got, err := New(cfg, nil, metrics.NewNop(), logger.New("error")).GenerateJSON(context.Background(), "hi", schema)
+	if err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+	if got != `{"title": "x"}` {
+		t.Errorf("GenerateJSON() = %q", got)
+	}
+}
@@ -0,0 +1,28 @@
+package logger
+
+import "context"
+
+type fieldsKey struct{}
+
+// WithFields returns a context carrying key/value pairs (e.g. "job", id, "stage", name)
+// that are attached to every log line written with that context.
+// Fields accumulate, so nested calls add to the fields of the parent context.
+func WithFields(ctx context.Context, keyvals ...interface{}) context.Context {
+	if len(keyvals) == 0 {
+		return ctx
+	}
+	parent := fieldsFrom(ctx)
+	fields := make([]interface{}, 0, len(parent)+len(keyvals))
+	fields = append(fields, parent...)
+	fields = append(fields, keyvals...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// fieldsFrom returns the key/value pairs stored in ctx
+func fieldsFrom(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
+	return fields
+}
@@ -3,22 +3,45 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
 type implLogger struct {
-	logger *log.Logger
+	logger *log.Logger  // text output
+	json   *slog.Logger // JSON output, nil in text mode
 	level  string
 }
 
-// New creates a new Logger instance
+// New creates a new Logger instance writing text lines
 func New(level string) Logger {
-	return &implLogger{
-		logger: log.New(os.Stdout, "", log.LstdFlags),
+	return NewWithFormat(level, FormatText)
+}
+
+// NewWithFormat creates a Logger writing either text lines ("text", the default)
+// or one JSON object per line ("json") with time, level, msg and the context fields
+func NewWithFormat(level, format string) Logger {
+	return newLogger(os.Stdout, level, format)
+}
+
+func newLogger(w io.Writer, level, format string) *implLogger {
+	l := &implLogger{
+		logger: log.New(w, "", log.LstdFlags),
 		level:  strings.ToLower(level),
 	}
+	if strings.ToLower(format) == FormatJSON {
+		// Level filtering is done by shouldLog so both formats behave the same
+		l.json = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	return l
 }
 
 func (l *implLogger) shouldLog(level string) bool {
@@ -44,26 +67,45 @@ func (l *implLogger) shouldLog(level string) bool {
 
 func (l *implLogger) Debug(ctx context.Context, msg string, args ...interface{}) {
 	if l.shouldLog("debug") {
-		l.logger.Printf("[DEBUG] "+msg, args...)
+		l.write(ctx, slog.LevelDebug, "[DEBUG] ", msg, args)
 	}
 }
 
 func (l *implLogger) Info(ctx context.Context, msg string, args ...interface{}) {
 	if l.shouldLog("info") {
-		l.logger.Printf("[INFO] "+msg, args...)
+		l.write(ctx, slog.LevelInfo, "[INFO] ", msg, args)
 	}
 }
 
 func (l *implLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
 	if l.shouldLog("warn") {
-		l.logger.Printf("[WARN] "+msg, args...)
+		l.write(ctx, slog.LevelWarn, "[WARN] ", msg, args)
 	}
 }
 
 func (l *implLogger) Error(ctx context.Context, msg string, args ...interface{}) {
 	if l.shouldLog("error") {
-		l.logger.Printf("[ERROR] "+msg, args...)
+		l.write(ctx, slog.LevelError, "[ERROR] ", msg, args)
+	}
+}
+
+// write formats msg printf-style and emits it with the fields carried by ctx
+func (l *implLogger) write(ctx context.Context, level slog.Level, prefix, msg string, args []interface{}) {
+	fields := fieldsFrom(ctx)
+
+	if l.json != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		l.json.Log(ctx, level, fmt.Sprintf(msg, args...), fields...)
+		return
+	}
+
+	line := fmt.Sprintf(prefix+msg, args...)
+	for i := 0; i+1 < len(fields); i += 2 {
+		line += fmt.Sprintf(" %v=%v", fields[i], fields[i+1])
 	}
+	l.logger.Print(line)
 }
 
 // Helper to format error messages
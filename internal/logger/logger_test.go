@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -65,3 +68,39 @@ func TestShouldLog(t *testing.T) {
 		})
 	}
 }
+
+func TestFormats(t *testing.T) {
+	ctx := WithFields(context.Background(), "job", "abc123")
+	ctx = WithFields(ctx, "stage", "burn")
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLogger(&buf, "info", "json")
+		log.Info(ctx, "stage done in %s", "2s")
+		log.Debug(ctx, "filtered out")
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("output is not a single JSON line: %q (%v)", buf.String(), err)
+		}
+		want := map[string]interface{}{"level": "INFO", "msg": "stage done in 2s", "job": "abc123", "stage": "burn"}
+		for k, v := range want {
+			if entry[k] != v {
+				t.Errorf("%s = %v, want %v", k, entry[k], v)
+			}
+		}
+		if _, ok := entry["time"]; !ok {
+			t.Error("missing time field")
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := newLogger(&buf, "info", "text")
+		log.Warn(ctx, "retrying %d", 2)
+
+		if got := buf.String(); !strings.Contains(got, "[WARN] retrying 2 job=abc123 stage=burn") {
+			t.Errorf("text output = %q", got)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+// Metrics defines the interface for recording pipeline metrics
+type Metrics interface {
+	// ObserveStage records the outcome and duration of a pipeline stage.
+	// mediaDuration is the length of the processed media; when positive the
+	// realtime factor (processing time ÷ media duration) is recorded as well.
+	ObserveStage(stage string, elapsed, mediaDuration time.Duration, err error)
+	// JobStarted and JobFinished track jobs currently being processed
+	JobStarted()
+	JobFinished()
+	// SemaphoreAcquired and SemaphoreReleased track watcher concurrency slot usage
+	SemaphoreAcquired()
+	SemaphoreReleased()
+	// LLMCall records an LLM request outcome per provider; rateLimited marks 429/quota errors
+	LLMCall(provider string, err error, rateLimited bool)
+	// LLMRetry records a retried LLM request
+	LLMRetry(provider string)
+	// Handler serves the metrics in Prometheus exposition format
+	Handler() http.Handler
+}
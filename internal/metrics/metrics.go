@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func (m *implMetrics) ObserveStage(stage string, elapsed, mediaDuration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.stageTotal.WithLabelValues(stage, result).Inc()
+	m.stageDuration.WithLabelValues(stage).Observe(elapsed.Seconds())
+
+	if err == nil && mediaDuration > 0 {
+		m.realtimeFactor.WithLabelValues(stage).Observe(elapsed.Seconds() / mediaDuration.Seconds())
+	}
+}
+
+func (m *implMetrics) JobStarted()  { m.jobsInFlight.Inc() }
+func (m *implMetrics) JobFinished() { m.jobsInFlight.Dec() }
+
+func (m *implMetrics) SemaphoreAcquired() { m.semaphoreInUse.Inc() }
+func (m *implMetrics) SemaphoreReleased() { m.semaphoreInUse.Dec() }
+
+func (m *implMetrics) LLMCall(provider string, err error, rateLimited bool) {
+	switch {
+	case rateLimited:
+		m.llmCalls.WithLabelValues(provider, "rate_limited").Inc()
+		m.llmLimited.WithLabelValues(provider).Inc()
+	case err != nil:
+		m.llmCalls.WithLabelValues(provider, "failure").Inc()
+	default:
+		m.llmCalls.WithLabelValues(provider, "success").Inc()
+	}
+}
+
+func (m *implMetrics) LLMRetry(provider string) { m.llmRetries.WithLabelValues(provider).Inc() }
+
+func (m *implMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlerExposesRecordedMetrics(t *testing.T) {
+	m := New()
+
+	m.ObserveStage("transcribe", 30*time.Second, 2*time.Minute, nil)
+	m.ObserveStage("burn", time.Second, time.Minute, errors.New("encoder failed"))
+	m.JobStarted()
+	m.SemaphoreAcquired()
+	m.LLMCall("gemini", errors.New("429"), true)
+	m.LLMRetry("gemini")
+	m.LLMCall("gemini", nil, false)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`caption_flow_stage_total{result="success",stage="transcribe"} 1`,
+		`caption_flow_stage_total{result="failure",stage="burn"} 1`,
+		`caption_flow_stage_realtime_factor_sum{stage="transcribe"} 0.25`,
+		`caption_flow_jobs_in_flight 1`,
+		`caption_flow_watcher_semaphore_in_use 1`,
+		`caption_flow_llm_calls_total{provider="gemini",result="rate_limited"} 1`,
+		`caption_flow_llm_calls_total{provider="gemini",result="success"} 1`,
+		`caption_flow_llm_retries_total{provider="gemini"} 1`,
+		`caption_flow_llm_rate_limited_total{provider="gemini"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+
+	// Failed stages do not skew the realtime factor
+	if strings.Contains(body, `caption_flow_stage_realtime_factor_count{stage="burn"}`) {
+		t.Error("realtime factor recorded for a failed stage")
+	}
+}
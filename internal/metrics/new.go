@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "caption_flow"
+
+type implMetrics struct {
+	registry *prometheus.Registry
+
+	stageTotal     *prometheus.CounterVec
+	stageDuration  *prometheus.HistogramVec
+	realtimeFactor *prometheus.HistogramVec
+	jobsInFlight   prometheus.Gauge
+	semaphoreInUse prometheus.Gauge
+	llmCalls       *prometheus.CounterVec
+	llmRetries     *prometheus.CounterVec
+	llmLimited     *prometheus.CounterVec
+}
+
+// New creates a Prometheus-backed Metrics instance with its own registry
+func New() Metrics {
+	m := &implMetrics{
+		registry: prometheus.NewRegistry(),
+		stageTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stage_total",
+			Help:      "Pipeline stage runs by stage and result (success, failure).",
+		}, []string{"stage", "result"}),
+		stageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "stage_duration_seconds",
+			Help:      "Pipeline stage duration in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14), // 1s .. ~2.3h
+		}, []string{"stage"}),
+		realtimeFactor: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "stage_realtime_factor",
+			Help:      "Stage processing time divided by media duration.",
+			Buckets:   []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1, 1.5, 2, 3, 5},
+		}, []string{"stage"}),
+		jobsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "jobs_in_flight",
+			Help:      "Videos currently being processed.",
+		}),
+		semaphoreInUse: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "watcher_semaphore_in_use",
+			Help:      "Occupied watcher concurrency slots.",
+		}),
+		llmCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_calls_total",
+			Help:      "LLM requests by provider and result (success, failure, rate_limited).",
+		}, []string{"provider", "result"}),
+		llmRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_retries_total",
+			Help:      "LLM requests retried after an error, by provider.",
+		}, []string{"provider"}),
+		llmLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_rate_limited_total",
+			Help:      "LLM requests rejected with 429 / quota errors, by provider.",
+		}, []string{"provider"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.stageTotal,
+		m.stageDuration,
+		m.realtimeFactor,
+		m.jobsInFlight,
+		m.semaphoreInUse,
+		m.llmCalls,
+		m.llmRetries,
+		m.llmLimited,
+	)
+	return m
+}
+
+// NewNop returns a Metrics that records nothing, used when metrics are disabled
+func NewNop() Metrics {
+	return nopMetrics{}
+}
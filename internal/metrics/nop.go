@@ -0,0 +1,17 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveStage(string, time.Duration, time.Duration, error) {}
+func (nopMetrics) JobStarted()                                              {}
+func (nopMetrics) JobFinished()                                             {}
+func (nopMetrics) SemaphoreAcquired()                                       {}
+func (nopMetrics) SemaphoreReleased()                                       {}
+func (nopMetrics) LLMCall(string, error, bool)                              {}
+func (nopMetrics) LLMRetry(string)                                          {}
+func (nopMetrics) Handler() http.Handler                                    { return http.NotFoundHandler() }
@@ -3,19 +3,52 @@ package processor
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
+// wavBytesPerSecond is the data rate of the extracted 16kHz mono 16-bit audio
+const wavBytesPerSecond = 16000 * 2
+
+// wavHeaderSize is the size of the canonical WAV header ffmpeg writes before the samples
+const wavHeaderSize = 44
+
 // extractAudio extracts audio from video file and converts to 16kHz mono WAV
 // This format is optimal for Whisper processing
 // Optimized for M4 Pro with faster processing
 func (p *implProcessor) extractAudio(ctx context.Context, videoPath string) (string, error) {
-	// Generate output audio path
-	audioPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_temp.wav"
+	audioPath := audioPathFor(videoPath)
 
 	p.logger.Info(ctx, "Extracting audio (optimized for M4 Pro): %s", videoPath)
 
+	if err := p.executor.Stream(ctx, "", p.ffmpegProgress(ctx), "ffmpeg", extractAudioArgs(videoPath, audioPath)...); err != nil {
+		return "", fmt.Errorf("ffmpeg extract audio: %w", err)
+	}
+
+	p.logger.Info(ctx, "Audio extracted successfully: %s", audioPath)
+	return audioPath, nil
+}
+
+// wavDuration estimates the length of an extracted WAV from its size. It stands in for the
+// media duration when ffprobe could not read the video.
+func wavDuration(audioPath string) (time.Duration, error) {
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return 0, err
+	}
+	samples := max(info.Size()-wavHeaderSize, 0)
+	return time.Duration(samples) * time.Second / wavBytesPerSecond, nil
+}
+
+// audioPathFor returns the temporary WAV path for videoPath
+func audioPathFor(videoPath string) string {
+	return strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_temp.wav"
+}
+
+// extractAudioArgs builds the ffmpeg arguments that extract 16kHz mono audio
+func extractAudioArgs(videoPath, audioPath string) []string {
 	// FFmpeg arguments for audio extraction
 	// -i: Input video
 	// -vn: No video (audio only)
@@ -24,7 +57,7 @@ func (p *implProcessor) extractAudio(ctx context.Context, videoPath string) (str
 	// -c:a pcm_s16le: PCM 16-bit little-endian format (uncompressed, best quality)
 	// -threads 0: Use all available CPU threads
 	// -y: Overwrite output file if exists
-	args := []string{
+	return append(append([]string{}, progressArgs...),
 		"-i", videoPath,
 		"-vn",          // No video
 		"-ar", "16000", // 16kHz sample rate
@@ -33,12 +66,5 @@ func (p *implProcessor) extractAudio(ctx context.Context, videoPath string) (str
 		"-threads", "0", // Use all available threads
 		"-y",
 		audioPath,
-	}
-
-	if _, err := p.executor.Execute(ctx, "ffmpeg", args...); err != nil {
-		return "", fmt.Errorf("ffmpeg extract audio: %w", err)
-	}
-
-	p.logger.Info(ctx, "Audio extracted successfully: %s", audioPath)
-	return audioPath, nil
+	)
 }
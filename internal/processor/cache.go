@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/cache"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/transcriber"
+)
+
+// transcriptKey builds the cache key for the job's transcript. The content hash is
+// computed once per job from the video or the extracted audio (cache.hash).
+// ok is false when the cache is disabled or hashing failed; the job then runs uncached.
+func (p *implProcessor) transcriptKey(ctx context.Context, job *jobstore.Job, videoPath, audioPath string, settings jobSettings) (cache.Key, bool) {
+	if p.cache == nil {
+		return cache.Key{}, false
+	}
+
+	prefix := p.cfg.Cache.Hash + ":"
+	if !strings.HasPrefix(job.ContentHash, prefix) {
+		source := videoPath
+		if p.cfg.Cache.Hash == "audio" {
+			source = audioPath
+		}
+		hash, err := cache.HashFile(source)
+		if err != nil {
+			p.logger.Warn(ctx, "Failed to hash %s, transcript cache skipped: %v", source, err)
+			return cache.Key{}, false
+		}
+		job.ContentHash = prefix + hash
+		p.saveJob(ctx, job)
+	}
+
+	return cache.Key{
+		Hash:     job.ContentHash,
+		Model:    p.transcriptModel(),
+		Language: settings.language,
+		Prompt:   settings.prompt,
+	}, true
+}
+
+// transcriptModel identifies the backend and model a transcript was made with
+func (p *implProcessor) transcriptModel() string {
+	switch p.cfg.Whisper.Backend {
+	case transcriber.BackendServer:
+		return "server:" + p.cfg.Whisper.ServerURL
+	case transcriber.BackendOpenAI:
+		return "openai:" + p.cfg.Whisper.APIModel
+	default:
+		return "cli:" + filepath.Base(p.cfg.Whisper.ModelPath)
+	}
+}
+
+// cachedTranscript returns the cache entry for key, or nil on a miss
+func (p *implProcessor) cachedTranscript(ctx context.Context, key cache.Key) *cache.Entry {
+	entry, err := p.cache.Lookup(ctx, key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			p.logger.Warn(ctx, "Failed to read transcript cache: %v", err)
+		}
+		return nil
+	}
+	return entry
+}
+
+// isDuplicate reports whether the job should be skipped because entry's job already
+// produced an output that still exists
+func (p *implProcessor) isDuplicate(entry *cache.Entry) bool {
+	if !p.cfg.Cache.SkipDuplicates || entry.Output == "" {
+		return false
+	}
+	_, err := os.Stat(entry.Output)
+	return err == nil
+}
+
+// restoreTranscript copies the cached SRT to where transcription would have written it
+func (p *implProcessor) restoreTranscript(ctx context.Context, entry *cache.Entry, audioPath string, settings jobSettings) (string, error) {
+	srtPath := transcribeRequest(audioPath, settings).OutputPath
+	data, err := os.ReadFile(entry.SRTPath)
+	if err != nil {
+		return "", fmt.Errorf("read cached transcript: %w", err)
+	}
+	if err := os.WriteFile(srtPath, data, 0644); err != nil {
+		return "", fmt.Errorf("write cached transcript: %w", err)
+	}
+
+	p.logger.Info(ctx, "Reusing cached transcript of %s", entry.Source)
+	return srtPath, nil
+}
+
+// storeTranscript adds a fresh transcript to the cache, logging instead of failing the job
+func (p *implProcessor) storeTranscript(ctx context.Context, key cache.Key, srtPath, videoPath string) {
+	if err := p.cache.Store(ctx, key, srtPath, videoPath); err != nil {
+		p.logger.Warn(ctx, "Failed to cache transcript: %v", err)
+	}
+}
+
+// recordOutput remembers the job's final output so later duplicates can point to it
+func (p *implProcessor) recordOutput(ctx context.Context, key cache.Key, output string) {
+	if err := p.cache.SetOutput(ctx, key, output); err != nil && !errors.Is(err, cache.ErrNotFound) {
+		p.logger.Warn(ctx, "Failed to record output in transcript cache: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/cache"
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+)
+
+func TestTranscriptCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := jobstore.New(filepath.Join(dir, "jobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := cache.New(filepath.Join(dir, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Whisper: config.WhisperConfig{Backend: "cli", ModelPath: "models/ggml-large-v3-turbo.bin"},
+		Cache:   config.CacheConfig{Enabled: true, Hash: "video", SkipDuplicates: true},
+	}
+	p := &implProcessor{cfg: cfg, cache: tc, store: store, logger: logger.New("error")}
+
+	// The same recording uploaded twice under different names
+	first := filepath.Join(dir, "lesson.mp4")
+	second := filepath.Join(dir, "lesson (1).mp4")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte("same recording"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settings := jobSettings{language: "en", prompt: "Kubernetes"}
+
+	job1, err := store.Open(ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, ok := p.transcriptKey(ctx, job1, first, "", settings)
+	if !ok {
+		t.Fatal("transcriptKey() not ok")
+	}
+	if key1.Model != "cli:ggml-large-v3-turbo.bin" {
+		t.Errorf("Model = %q", key1.Model)
+	}
+	if p.cachedTranscript(ctx, key1) != nil {
+		t.Fatal("cachedTranscript() hit on empty cache")
+	}
+
+	srt := filepath.Join(dir, "lesson.srt")
+	if err := os.WriteFile(srt, []byte("1\n00:00:00,000 --> 00:00:01,000\nhello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p.storeTranscript(ctx, key1, srt, first)
+
+	job2, err := store.Open(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, _ := p.transcriptKey(ctx, job2, second, "", settings)
+	entry := p.cachedTranscript(ctx, key2)
+	if entry == nil || entry.Source != first {
+		t.Fatalf("cachedTranscript() = %+v, want entry from %s", entry, first)
+	}
+	if p.isDuplicate(entry) {
+		t.Error("isDuplicate() = true before the first job recorded an output")
+	}
+
+	p.recordOutput(ctx, key1, first) // any existing file stands in for the output video
+	if entry = p.cachedTranscript(ctx, key2); !p.isDuplicate(entry) {
+		t.Error("isDuplicate() = false after the first job recorded an output")
+	}
+
+	// A different prompt must not reuse the transcript
+	key3, _ := p.transcriptKey(ctx, job2, second, "", jobSettings{language: "en"})
+	if p.cachedTranscript(ctx, key3) != nil {
+		t.Error("cachedTranscript() hit for a different prompt")
+	}
+}
@@ -0,0 +1,62 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/chapters"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// generateChapters asks the LLM to split the transcript into chapters and writes them to the
+// output folder as <baseName>.chapters.txt (YouTube description lines) and
+// <baseName>.chapters.vtt. When the job burns, an ffmetadata file for the burn is also
+// written next to the temp transcript. Returns the written paths keyed "youtube", "vtt"
+// and "metadata".
+func (p *implProcessor) generateChapters(ctx context.Context, srtPath, baseName, outDir string, duration time.Duration, burn bool) (map[string]string, error) {
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return nil, fmt.Errorf("read SRT: %w", err)
+	}
+	if duration <= 0 && len(cues) > 0 {
+		duration = cues[len(cues)-1].End
+	}
+
+	p.logger.Info(ctx, "Generating chapters from %d cues", len(cues))
+	list, err := p.chapters.Generate(ctx, cues, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	youtubePath := filepath.Join(p.cfg.Paths.Output, outDir, baseName+".chapters.txt")
+	vttPath := filepath.Join(p.cfg.Paths.Output, outDir, baseName+".chapters.vtt")
+	if err := os.MkdirAll(filepath.Dir(youtubePath), 0755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+	contents := map[string]string{
+		"youtube": chapters.FormatYouTube(list),
+		"vtt":     chapters.FormatWebVTT(list, duration),
+	}
+	written := map[string]string{"youtube": youtubePath, "vtt": vttPath}
+	if burn {
+		contents["metadata"] = chapters.FormatFFMetadata(list, duration)
+		written["metadata"] = chaptersMetadataPathFor(srtPath)
+	}
+	for key, path := range written {
+		if err := os.WriteFile(path, []byte(contents[key]), 0644); err != nil {
+			return nil, fmt.Errorf("write chapters: %w", err)
+		}
+	}
+
+	p.logger.Info(ctx, "Chapters saved: %s (%d chapters)", youtubePath, len(list))
+	return written, nil
+}
+
+// chaptersMetadataPathFor is the temp path of the ffmetadata file embedded by the burn
+func chaptersMetadataPathFor(srtPath string) string {
+	return strings.TrimSuffix(srtPath, ".srt") + ".chapters.ffmetadata"
+}
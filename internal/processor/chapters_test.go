@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/chapters"
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// fixedChapters returns its chapters and records the duration it was given
+type fixedChapters struct {
+	list     []chapters.Chapter
+	duration time.Duration
+}
+
+func (f *fixedChapters) Generate(_ context.Context, _ []subtitle.Cue, duration time.Duration) ([]chapters.Chapter, error) {
+	f.duration = duration
+	return f.list, nil
+}
+
+func TestGenerateChapters(t *testing.T) {
+	dir := t.TempDir()
+	srtPath := filepath.Join(dir, "intro_temp.srt")
+	cues := []subtitle.Cue{{Index: 1, Start: 0, End: 2 * time.Second, Text: "hi"}, {Index: 2, Start: 100 * time.Second, End: 2 * time.Minute, Text: "bye"}}
+	if err := subtitle.WriteSRTFile(srtPath, cues); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := &fixedChapters{list: []chapters.Chapter{{Start: 0, Title: "Intro"}, {Start: time.Minute, Title: "Build"}}}
+	p := &implProcessor{
+		cfg:      &config.Config{Paths: config.PathsConfig{Output: filepath.Join(dir, "output")}},
+		chapters: gen,
+		logger:   logger.New("error"),
+	}
+	written, err := p.generateChapters(context.Background(), srtPath, "intro", "course", 0, true)
+	if err != nil {
+		t.Fatalf("generateChapters() error = %v", err)
+	}
+	if gen.duration != 2*time.Minute {
+		t.Errorf("duration = %v, want the last cue end when unprobed", gen.duration)
+	}
+
+	want := map[string]string{
+		"youtube":  "00:00 Intro\n01:00 Build\n",
+		"vtt":      "00:01:00.000 --> 00:02:00.000\nBuild",
+		"metadata": "START=60000\nEND=120000\ntitle=Build",
+	}
+	for key, content := range want {
+		data, err := os.ReadFile(written[key])
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if !strings.Contains(string(data), content) {
+			t.Errorf("%s = %q, want it to contain %q", key, data, content)
+		}
+	}
+	if got := written["youtube"]; got != filepath.Join(dir, "output", "course", "intro.chapters.txt") {
+		t.Errorf("youtube path = %s", got)
+	}
+	if got := written["metadata"]; got != filepath.Join(dir, "intro_temp.chapters.ffmetadata") {
+		t.Errorf("metadata path = %s", got)
+	}
+
+	// Without a burn nothing reads the metadata, so it is not written
+	os.Remove(written["metadata"])
+	written, err = p.generateChapters(context.Background(), srtPath, "intro", "course", 0, false)
+	if err != nil {
+		t.Fatalf("generateChapters() error = %v", err)
+	}
+	if _, ok := written["metadata"]; ok {
+		t.Errorf("metadata written without a burn: %v", written)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "intro_temp.chapters.ffmetadata")); !os.IsNotExist(err) {
+		t.Errorf("metadata file exists without a burn")
+	}
+}
+
+func TestBurnArgsChapters(t *testing.T) {
+	p := &implProcessor{cfg: &config.Config{FFmpeg: config.FFmpegConfig{Encoder: "libx264", VideoBitrate: "5M", AudioCodec: "copy"}}}
+
+	got := strings.Join(p.burnArgs("/in/video.mp4", "subtitle.ass", "/tmp/a.chapters.ffmetadata", "/tmp/out.mp4"), " ")
+	want := "-progress pipe:1 -nostats -y -i /in/video.mp4 -i /tmp/a.chapters.ffmetadata -map_chapters 1 " +
+		"-vf subtitles=subtitle.ass -c:v libx264 -b:v 5M -c:a copy /tmp/out.mp4"
+	if got != want {
+		t.Errorf("burnArgs() =\n%s\nwant\n%s", got, want)
+	}
+
+	got = strings.Join(p.burnArgs("/in/video.mp4", "subtitle.ass", "", "/tmp/out.mp4"), " ")
+	if strings.Contains(got, "-map_chapters") {
+		t.Errorf("burnArgs() without chapters = %s", got)
+	}
+}
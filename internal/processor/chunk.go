@@ -0,0 +1,327 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/transcriber"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// reSilence matches ffmpeg silencedetect output, e.g.
+// "[silencedetect @ 0x600] silence_start: 12.34" and "silence_end: 14.1 | silence_duration: 1.76"
+var reSilence = regexp.MustCompile(`silence_(start|end):\s*(-?\d+(?:\.\d+)?)`)
+
+// silence is a quiet span of the audio
+type silence struct {
+	start, end time.Duration
+}
+
+// audioChunk is a slice of the audio transcribed on its own. The audio cut includes the
+// overlap with its neighbours; a cue belongs to the chunk whose [from, to) contains its midpoint.
+type audioChunk struct {
+	path       string
+	start, end time.Duration // audio cut, including the overlap
+	from, to   time.Duration // part of the timeline this chunk owns
+}
+
+// shouldChunk reports whether a recording of duration is split before transcription. The
+// openai backend needs chunks whenever the audio exceeds its upload limit.
+func (p *implProcessor) shouldChunk(duration time.Duration) bool {
+	chunking := p.cfg.Whisper.Chunking
+	if p.cfg.Whisper.Backend == transcriber.BackendOpenAI && duration > config.OpenAIMaxAudio {
+		return true
+	}
+	return chunking.Enabled && duration > 0 && duration >= chunking.MinDuration && duration > chunking.ChunkDuration
+}
+
+// transcribeChunked splits the audio on silence, transcribes the chunks in parallel and
+// stitches their cues back into one SRT at the usual output path
+func (p *implProcessor) transcribeChunked(ctx context.Context, audioPath string, settings jobSettings, duration time.Duration) (string, error) {
+	chunking := p.cfg.Whisper.Chunking
+
+	silences, err := p.detectSilences(ctx, audioPath, chunking.SilenceThreshold, chunking.SilenceDuration)
+	if err != nil {
+		return "", err
+	}
+	chunks := planChunks(duration, silences, chunking)
+	base := strings.TrimSuffix(audioPath, filepath.Ext(audioPath))
+	for i := range chunks {
+		chunks[i].path = fmt.Sprintf("%s_chunk%03d.wav", base, i)
+	}
+	defer func() {
+		// Chunks are cheap to recut, so they are removed even when the stage fails
+		for _, chunk := range chunks {
+			os.Remove(chunk.path)
+			os.Remove(srtPathFor(chunk.path))
+		}
+	}()
+
+	parallel := min(chunking.Parallel, len(chunks))
+	p.logger.Info(ctx, "Transcribing %s in %d chunks (%d silences found, %d at a time)",
+		duration.Round(time.Second), len(chunks), len(silences), parallel)
+
+	for _, chunk := range chunks {
+		if _, err := p.executor.Execute(ctx, "ffmpeg", cutChunkArgs(audioPath, chunk)...); err != nil {
+			return "", fmt.Errorf("ffmpeg cut chunk: %w", err)
+		}
+	}
+
+	results, err := p.transcribeChunks(ctx, chunks, settings, parallel)
+	if err != nil {
+		return "", err
+	}
+
+	cues := stitchChunks(chunks, results)
+	srtPath := transcribeRequest(audioPath, settings).OutputPath
+	if err := subtitle.WriteSRTFile(srtPath, cues); err != nil {
+		return "", fmt.Errorf("write stitched subtitle: %w", err)
+	}
+
+	p.logger.Info(ctx, "Transcription completed: %s (%d cues from %d chunks)", srtPath, len(cues), len(chunks))
+	return srtPath, nil
+}
+
+// transcribeChunks runs up to parallel transcriptions at once and returns each chunk's
+// cues relative to the chunk start. whisper.threads is divided between the workers.
+func (p *implProcessor) transcribeChunks(ctx context.Context, chunks []audioChunk, settings jobSettings, parallel int) ([][]subtitle.Cue, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	threads := max(1, p.cfg.Whisper.Threads/parallel)
+	results := make([][]subtitle.Cue, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, parallel)
+
+	var mu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			req := transcriber.Request{
+				AudioPath:  chunk.path,
+				OutputPath: srtPathFor(chunk.path),
+				Language:   settings.language,
+				Prompt:     settings.prompt,
+				Threads:    threads,
+			}
+			if err := p.transcriber.Transcribe(ctx, req); err != nil {
+				errs[i] = fmt.Errorf("chunk %d: %s backend: %w", i, p.cfg.Whisper.Backend, err)
+				cancel() // one failed chunk fails the whole transcript
+				return
+			}
+			cues, err := subtitle.ReadSRTFile(req.OutputPath)
+			if err != nil {
+				errs[i] = fmt.Errorf("chunk %d: %w", i, err)
+				cancel()
+				return
+			}
+			results[i] = cues
+
+			mu.Lock()
+			done++
+			p.reportProgress(ctx, 100*float64(done)/float64(len(chunks)))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Report the first real failure rather than the cancellations it caused
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// detectSilences runs ffmpeg silencedetect over the audio, finding spans of at least
+// minDuration quieter than threshold (e.g. "-35dB")
+func (p *implProcessor) detectSilences(ctx context.Context, audioPath, threshold string, minDuration time.Duration) ([]silence, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%s:d=%s", threshold, formatSeconds(minDuration))
+
+	var lines []string
+	collect := func(line string) {
+		if strings.Contains(line, "silence_") {
+			lines = append(lines, line)
+		}
+	}
+	if err := p.executor.Stream(ctx, "", collect, "ffmpeg", "-hide_banner", "-nostats", "-i", audioPath, "-af", filter, "-f", "null", "-"); err != nil {
+		return nil, fmt.Errorf("ffmpeg silencedetect: %w", err)
+	}
+	return parseSilences(lines), nil
+}
+
+// parseSilences pairs silencedetect start/end lines into spans. A silence still open
+// at the end of the audio has no end line and is dropped.
+func parseSilences(lines []string) []silence {
+	var silences []silence
+	open := time.Duration(-1)
+	for _, line := range lines {
+		m := reSilence.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		at := max(time.Duration(seconds*float64(time.Second)), 0)
+		switch {
+		case m[1] == "start":
+			open = at
+		case open >= 0:
+			silences = append(silences, silence{start: open, end: at})
+			open = -1
+		}
+	}
+	return silences
+}
+
+// planChunks cuts total into chunks of at most chunk_duration. Each cut goes into the
+// middle of the longest silence in the second half of the chunk, or at its end when
+// that half has no silence; the overlap covers words split by such hard cuts.
+func planChunks(total time.Duration, silences []silence, chunking config.ChunkingConfig) []audioChunk {
+	maxLen := chunking.ChunkDuration
+	bounds := []time.Duration{0}
+	for pos := time.Duration(0); total-pos > maxLen; {
+		lo, hi := pos+maxLen/2, pos+maxLen
+		cut, longest := hi, time.Duration(0)
+		for _, s := range silences {
+			mid := (s.start + s.end) / 2
+			if mid >= lo && mid <= hi && s.end-s.start > longest {
+				cut, longest = mid, s.end-s.start
+			}
+		}
+		bounds = append(bounds, cut)
+		pos = cut
+	}
+	bounds = append(bounds, total)
+
+	chunks := make([]audioChunk, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		chunks = append(chunks, audioChunk{
+			start: max(bounds[i]-chunking.Overlap, 0),
+			end:   min(bounds[i+1]+chunking.Overlap, total),
+			from:  bounds[i],
+			to:    bounds[i+1],
+		})
+	}
+	return chunks
+}
+
+// cutChunkArgs builds the ffmpeg arguments that copy chunk's span of the WAV
+func cutChunkArgs(audioPath string, chunk audioChunk) []string {
+	return []string{
+		"-y",
+		"-ss", formatSeconds(chunk.start),
+		"-t", formatSeconds(chunk.end - chunk.start),
+		"-i", audioPath,
+		"-c", "copy", // PCM cuts are sample accurate without re-encoding
+		chunk.path,
+	}
+}
+
+// stitchChunks shifts each chunk's cues onto the full timeline, keeps the cues each chunk
+// owns and removes what the overlap transcribed twice: a cue repeating the text of the
+// cue it overlaps is merged into it, other overlaps are trimmed so cues never intersect
+func stitchChunks(chunks []audioChunk, results [][]subtitle.Cue) []subtitle.Cue {
+	var out []subtitle.Cue
+	for i, chunk := range chunks {
+		last := i == len(chunks)-1
+		for _, c := range results[i] {
+			c.Start += chunk.start
+			c.End += chunk.start
+			if mid := (c.Start + c.End) / 2; mid < chunk.from || (mid >= chunk.to && !last) {
+				continue
+			}
+
+			if n := len(out); n > 0 && c.Start < out[n-1].End {
+				prev := &out[n-1]
+				if sameSpeech(prev.Text, c.Text) {
+					// Keep the fuller transcription of the words heard twice
+					if len(normalizeSpeech(c.Text)) > len(normalizeSpeech(prev.Text)) {
+						prev.Text = c.Text
+					}
+					prev.End = max(prev.End, c.End)
+					continue
+				}
+				if c.Start > prev.Start {
+					prev.End = c.Start
+				} else {
+					c.Start = prev.End
+				}
+				if c.End <= c.Start {
+					continue
+				}
+			}
+			out = append(out, c)
+		}
+	}
+	subtitle.Renumber(out)
+	return out
+}
+
+// sameSpeech reports whether two cue texts are the same words, ignoring case and
+// punctuation, or one is a fragment of the other
+func sameSpeech(a, b string) bool {
+	na, nb := normalizeSpeech(a), normalizeSpeech(b)
+	if na == "" || nb == "" {
+		return false
+	}
+	return strings.Contains(na, nb) || strings.Contains(nb, na)
+}
+
+func normalizeSpeech(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && sb.Len() > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	return sb.String()
+}
+
+// srtPathFor returns the SRT path next to a chunk's audio
+func srtPathFor(audioPath string) string {
+	return strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".srt"
+}
+
+// formatSeconds renders d as ffmpeg seconds with millisecond precision
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,123 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+func TestParseSilences(t *testing.T) {
+	lines := []string{
+		"[silencedetect @ 0x6000] silence_start: 12.5",
+		"[silencedetect @ 0x6000] silence_end: 14 | silence_duration: 1.5",
+		"[silencedetect @ 0x6000] silence_start: -0.01",
+		"[silencedetect @ 0x6000] silence_end: 0.8 | silence_duration: 0.81",
+		"[silencedetect @ 0x6000] silence_start: 99.2", // still silent at the end
+	}
+
+	got := parseSilences(lines)
+	want := []silence{
+		{start: 12500 * time.Millisecond, end: 14 * time.Second},
+		{start: 0, end: 800 * time.Millisecond},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseSilences() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("silence %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPlanChunks(t *testing.T) {
+	chunking := config.ChunkingConfig{ChunkDuration: 10 * time.Minute, Overlap: 2 * time.Second}
+	silences := []silence{
+		{start: 3 * time.Minute, end: 3*time.Minute + 5*time.Second}, // first half, ignored
+		{start: 7 * time.Minute, end: 7*time.Minute + time.Second},   // shorter
+		{start: 8 * time.Minute, end: 8*time.Minute + 4*time.Second}, // longest in window
+		{start: 12 * time.Minute, end: 12*time.Minute + time.Second}, // first half of chunk 2
+	}
+
+	chunks := planChunks(25*time.Minute, silences, chunking)
+
+	// 0 -> 8m2s (silence) -> 18m2s (hard cut, no silence) -> 25m
+	wantBounds := []time.Duration{0, 8*time.Minute + 2*time.Second, 18*time.Minute + 2*time.Second, 25 * time.Minute}
+	if len(chunks) != len(wantBounds)-1 {
+		t.Fatalf("planChunks() = %d chunks, want %d", len(chunks), len(wantBounds)-1)
+	}
+	for i, c := range chunks {
+		if c.from != wantBounds[i] || c.to != wantBounds[i+1] {
+			t.Errorf("chunk %d owns %s-%s, want %s-%s", i, c.from, c.to, wantBounds[i], wantBounds[i+1])
+		}
+	}
+	if chunks[0].start != 0 || chunks[0].end != chunks[0].to+2*time.Second {
+		t.Errorf("chunk 0 cut = %s-%s", chunks[0].start, chunks[0].end)
+	}
+	if last := chunks[2]; last.start != last.from-2*time.Second || last.end != 25*time.Minute {
+		t.Errorf("chunk 2 cut = %s-%s", last.start, last.end)
+	}
+}
+
+func TestStitchChunks(t *testing.T) {
+	sec := func(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+	chunks := []audioChunk{
+		{start: 0, end: sec(62), from: 0, to: sec(60)},
+		{start: sec(58), end: sec(120), from: sec(60), to: sec(120)},
+	}
+	results := [][]subtitle.Cue{
+		{
+			{Start: sec(1), End: sec(4), Text: "Welcome to the course."},
+			{Start: sec(57), End: sec(61), Text: "Let's get started"}, // midpoint 59s, owned here
+			{Start: sec(61), End: sec(62), Text: "with"},              // in the overlap, owned by chunk 1
+		},
+		{
+			// Offsets are relative to the chunk start (58s)
+			{Start: sec(0.5), End: sec(3), Text: "let's get started."},   // 58.5-61: midpoint 59.75 -> chunk 0, dropped
+			{Start: sec(2), End: sec(5), Text: "Let's get started with"}, // 60-63: same speech as the cue it overlaps
+			{Start: sec(5), End: sec(8), Text: "Kubernetes."},            // 63-66
+		},
+	}
+
+	got := stitchChunks(chunks, results)
+	want := []subtitle.Cue{
+		{Index: 1, Start: sec(1), End: sec(4), Text: "Welcome to the course."},
+		{Index: 2, Start: sec(57), End: sec(63), Text: "Let's get started with"},
+		{Index: 3, Start: sec(63), End: sec(66), Text: "Kubernetes."},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("stitchChunks() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("cue %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShouldChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  string
+		enabled  bool
+		duration time.Duration
+		want     bool
+	}{
+		{"disabled", "cli", false, time.Hour, false},
+		{"enabled and long", "cli", true, time.Hour, true},
+		{"enabled but short", "cli", true, 20 * time.Minute, false},
+		{"openai over the upload limit", "openai", false, 20 * time.Minute, true},
+		{"openai under the upload limit", "openai", false, 10 * time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunking := config.ChunkingConfig{Enabled: tt.enabled, MinDuration: 30 * time.Minute, ChunkDuration: 10 * time.Minute}
+			p := &implProcessor{cfg: &config.Config{Whisper: config.WhisperConfig{Backend: tt.backend, Chunking: chunking}}}
+			if got := p.shouldChunk(tt.duration); got != tt.want {
+				t.Errorf("shouldChunk(%s) = %v, want %v", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
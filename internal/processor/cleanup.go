@@ -5,40 +5,57 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
 )
 
-// moveToArchived moves original video to archived folder after successful processing
-func (p *implProcessor) moveToArchived(ctx context.Context, videoPath string) error {
+// moveToArchived moves original video to archived folder after successful processing,
+// keeping its subfolder relative to the input folder
+func (p *implProcessor) moveToArchived(ctx context.Context, videoPath, relDir string) (string, error) {
 	// Ensure archived folder exists
-	if err := os.MkdirAll(p.cfg.Paths.Archived, 0755); err != nil {
-		return fmt.Errorf("create archived folder: %w", err)
+	destPath := p.archivePath(videoPath, relDir)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", fmt.Errorf("create archived folder: %w", err)
 	}
 
-	filename := filepath.Base(videoPath)
-	destPath := filepath.Join(p.cfg.Paths.Archived, filename)
 	p.logger.Info(ctx, "Moving original video to archived: %s -> %s", videoPath, destPath)
 
 	if err := os.Rename(videoPath, destPath); err != nil {
-		return fmt.Errorf("move to archived: %w", err)
+		return "", fmt.Errorf("move to archived: %w", err)
 	}
 
-	return nil
+	return destPath, nil
 }
 
-// copySRT copies subtitle file to output folder
-func (p *implProcessor) copySRT(ctx context.Context, srtPath, destPath string) error {
-	p.logger.Info(ctx, "Copying SRT to output: %s -> %s", srtPath, destPath)
+// archivePath is where the original video is moved: <archived>/<relDir>/<filename>
+func (p *implProcessor) archivePath(videoPath, relDir string) string {
+	return filepath.Join(p.cfg.Paths.Archived, relDir, filepath.Base(videoPath))
+}
 
-	data, err := os.ReadFile(srtPath)
+// tempDirFor is the scratch folder of a stage (burn, mux) working on path. It is named
+// after the job rather than randomly, so a dry-run plan shows the folder a run uses.
+func (p *implProcessor) tempDirFor(kind, path string) (string, error) {
+	id, err := jobstore.IDFor(path)
 	if err != nil {
-		return fmt.Errorf("read SRT: %w", err)
+		return "", err
 	}
+	return filepath.Join(p.cfg.Paths.Temp, kind+"-"+id), nil
+}
 
-	if err := os.WriteFile(destPath, data, 0644); err != nil {
-		return fmt.Errorf("write SRT to output: %w", err)
+// createTempDir creates the empty scratch folder from tempDirFor, clearing what a crashed
+// run may have left behind
+func (p *implProcessor) createTempDir(kind, path string) (string, error) {
+	dir, err := p.tempDirFor(kind, path)
+	if err != nil {
+		return "", err
 	}
-
-	return nil
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
 }
 
 // cleanupTempFile removes a temporary file, logs warning if fails
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// exportSubtitles writes the transcribed cues to the output folder (below outDir) as
+// <baseName>.srt plus every extra format listed in subtitles.formats.
+// Returns the written paths keyed by format.
+func (p *implProcessor) exportSubtitles(ctx context.Context, srtPath, baseName, outDir string) (map[string]string, error) {
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return nil, fmt.Errorf("read SRT: %w", err)
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("no subtitle cues in %s", srtPath)
+	}
+
+	formats, err := p.exportFormats()
+	if err != nil {
+		return nil, err
+	}
+
+	destDir := filepath.Join(p.cfg.Paths.Output, outDir)
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+
+	written := make(map[string]string, len(formats))
+	for _, format := range formats {
+		destPath := filepath.Join(destDir, baseName+format.Extension())
+		p.logger.Info(ctx, "Writing %s subtitle: %s", format, destPath)
+
+		if err := subtitle.WriteFile(destPath, format, cues); err != nil {
+			return written, fmt.Errorf("write %s subtitle: %w", format, err)
+		}
+		written[string(format)] = destPath
+	}
+
+	return written, nil
+}
+
+// exportFormats returns SRT followed by the extra formats from subtitles.formats
+func (p *implProcessor) exportFormats() ([]subtitle.Format, error) {
+	formats := []subtitle.Format{subtitle.SRT}
+	for _, name := range p.cfg.Subtitles.Formats {
+		format, err := subtitle.ParseFormat(name)
+		if err != nil {
+			return nil, err
+		}
+		if format != subtitle.SRT {
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
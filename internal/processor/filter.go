@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// Reasons a cue is removed by the hallucination filter
+const (
+	reasonPhrase  = "phrase"
+	reasonSilence = "silence"
+	reasonRepeat  = "repeat"
+)
+
+// removedCue is a cue dropped by the hallucination filter
+type removedCue struct {
+	cue    subtitle.Cue
+	reason string
+}
+
+// filterSubtitles drops hallucinated cues from the transcript, writes the rest next to it
+// and a report of the removed cues to the output folder as <baseName>.filtered.txt.
+// Returns the written paths keyed "srt" and "report".
+func (p *implProcessor) filterSubtitles(ctx context.Context, srtPath, audioPath, baseName, outDir string) (map[string]string, error) {
+	cfg := p.cfg.Filter
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return nil, fmt.Errorf("read SRT: %w", err)
+	}
+
+	var silences []silence
+	if cfg.DropSilent {
+		if silences, err = p.detectSilences(ctx, audioPath, cfg.SilenceThreshold, cfg.SilenceDuration); err != nil {
+			return nil, err
+		}
+	}
+
+	kept, removed := filterCues(cues, silences, cfg)
+	counts := make(map[string]int)
+	for _, r := range removed {
+		counts[r.reason]++
+		p.logger.Debug(ctx, "Filtered cue %d (%s): %q", r.cue.Index, r.reason, r.cue.Text)
+	}
+	p.logger.Info(ctx, "Filter removed %d of %d cues (phrase: %d, silence: %d, repeat: %d)",
+		len(removed), len(cues), counts[reasonPhrase], counts[reasonSilence], counts[reasonRepeat])
+
+	destPath := filteredPathFor(srtPath)
+	if err := subtitle.WriteSRTFile(destPath, kept); err != nil {
+		return nil, fmt.Errorf("write filtered subtitle: %w", err)
+	}
+
+	reportPath := filepath.Join(p.cfg.Paths.Output, outDir, baseName+".filtered.txt")
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+	if err := os.WriteFile(reportPath, []byte(filterReport(baseName, len(cues), removed)), 0644); err != nil {
+		return nil, fmt.Errorf("write filter report: %w", err)
+	}
+
+	return map[string]string{"srt": destPath, "report": reportPath}, nil
+}
+
+// filterCues drops cues that are a whole hallucination phrase, that lie within silence,
+// and the rest of any run of identical consecutive cues longer than filter.max_repeats.
+// Kept cues are renumbered; removed ones keep their original index.
+func filterCues(cues []subtitle.Cue, silences []silence, cfg config.FilterConfig) ([]subtitle.Cue, []removedCue) {
+	phrases := make(map[string]bool, len(cfg.Phrases))
+	for _, phrase := range cfg.Phrases {
+		phrases[normalizeSpeech(phrase)] = true
+	}
+
+	var candidates []subtitle.Cue
+	var removed []removedCue
+	for _, c := range cues {
+		switch {
+		case phrases[normalizeSpeech(c.Text)]:
+			removed = append(removed, removedCue{cue: c, reason: reasonPhrase})
+		case len(silences) > 0 && silentShare(c, silences) >= cfg.SilenceCoverage:
+			removed = append(removed, removedCue{cue: c, reason: reasonSilence})
+		default:
+			candidates = append(candidates, c)
+		}
+	}
+
+	var kept []subtitle.Cue
+	for i := 0; i < len(candidates); {
+		// Find the run of cues saying the same thing as candidates[i]
+		text := normalizeSpeech(candidates[i].Text)
+		j := i + 1
+		for j < len(candidates) && text != "" && normalizeSpeech(candidates[j].Text) == text {
+			j++
+		}
+		if j-i > cfg.MaxRepeats {
+			kept = append(kept, candidates[i])
+			for _, c := range candidates[i+1 : j] {
+				removed = append(removed, removedCue{cue: c, reason: reasonRepeat})
+			}
+		} else {
+			kept = append(kept, candidates[i:j]...)
+		}
+		i = j
+	}
+
+	subtitle.Renumber(kept)
+	return kept, removed
+}
+
+// silentShare returns the fraction of the cue's time that falls within silences
+func silentShare(c subtitle.Cue, silences []silence) float64 {
+	if c.Duration() <= 0 {
+		return 0
+	}
+	var silent time.Duration
+	for _, s := range silences {
+		if overlap := min(c.End, s.end) - max(c.Start, s.start); overlap > 0 {
+			silent += overlap
+		}
+	}
+	return float64(silent) / float64(c.Duration())
+}
+
+// filterReport lists the removed cues in transcript order with the reason for each
+func filterReport(name string, total int, removed []removedCue) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Removed %d of %d cues from %s\n", len(removed), total, name)
+	ordered := make([]removedCue, len(removed))
+	copy(ordered, removed)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].cue.Start < ordered[j].cue.Start
+	})
+	for _, r := range ordered {
+		fmt.Fprintf(&sb, "\n#%d %s --> %s [%s]\n%s\n", r.cue.Index,
+			subtitle.FormatTimestamp(r.cue.Start), subtitle.FormatTimestamp(r.cue.End), r.reason, r.cue.Text)
+	}
+	return sb.String()
+}
+
+// filteredPathFor is the temp path of the filtered transcript
+func filteredPathFor(srtPath string) string {
+	return strings.TrimSuffix(srtPath, ".srt") + ".filtered.srt"
+}
@@ -0,0 +1,66 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+func TestFilterCues(t *testing.T) {
+	cfg := config.FilterConfig{
+		MaxRepeats:      2,
+		Phrases:         config.DefaultHallucinationPhrases,
+		SilenceCoverage: 0.9,
+	}
+	sec := func(s int) time.Duration { return time.Duration(s) * time.Second }
+	cues := []subtitle.Cue{
+		{Index: 1, Start: sec(0), End: sec(2), Text: "Welcome back."},
+		{Index: 2, Start: sec(2), End: sec(4), Text: "Okay."},
+		{Index: 3, Start: sec(4), End: sec(6), Text: "okay"}, // a run of two is kept
+		{Index: 4, Start: sec(6), End: sec(8), Text: "Let's deploy."},
+		{Index: 5, Start: sec(8), End: sec(10), Text: "Let's deploy."},
+		{Index: 6, Start: sec(10), End: sec(12), Text: "Let's deploy!"},
+		{Index: 7, Start: sec(12), End: sec(14), Text: "Let's deploy."},
+		{Index: 8, Start: sec(20), End: sec(24), Text: "Hmm."}, // over silence
+		{Index: 9, Start: sec(30), End: sec(32), Text: "Thanks for watching!"},
+		{Index: 10, Start: sec(32), End: sec(34), Text: "Thanks for watching, see you in part two."},
+	}
+	silences := []silence{{start: sec(19), end: sec(29)}}
+
+	kept, removed := filterCues(cues, silences, cfg)
+
+	var keptText []string
+	for _, c := range kept {
+		keptText = append(keptText, c.Text)
+	}
+	want := "Welcome back.|Okay.|okay|Let's deploy.|Thanks for watching, see you in part two."
+	if got := strings.Join(keptText, "|"); got != want {
+		t.Errorf("kept = %s\nwant   %s", got, want)
+	}
+	if kept[len(kept)-1].Index != len(kept) {
+		t.Errorf("kept cues not renumbered: last index %d", kept[len(kept)-1].Index)
+	}
+
+	reasons := make(map[int]string)
+	for _, r := range removed {
+		reasons[r.cue.Index] = r.reason
+	}
+	wantReasons := map[int]string{5: reasonRepeat, 6: reasonRepeat, 7: reasonRepeat, 8: reasonSilence, 9: reasonPhrase}
+	if len(reasons) != len(wantReasons) {
+		t.Fatalf("removed = %v, want %v", reasons, wantReasons)
+	}
+	for index, reason := range wantReasons {
+		if reasons[index] != reason {
+			t.Errorf("cue %d removed for %q, want %q", index, reasons[index], reason)
+		}
+	}
+
+	report := filterReport("lesson", len(cues), removed)
+	if !strings.HasPrefix(report, "Removed 5 of 10 cues from lesson\n") ||
+		!strings.Contains(report, "#8 00:00:20,000 --> 00:00:24,000 [silence]\nHmm.\n") {
+		t.Errorf("filterReport() =\n%s", report)
+	}
+}
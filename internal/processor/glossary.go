@@ -0,0 +1,41 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// correctSubtitles replaces misrecognized terms with their glossary spelling, logging
+// every correction, and writes the result next to the transcript
+func (p *implProcessor) correctSubtitles(ctx context.Context, srtPath string) (string, error) {
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return "", fmt.Errorf("read SRT: %w", err)
+	}
+
+	total := 0
+	for i := range cues {
+		text, corrections := p.glossary.Apply(cues[i].Text)
+		for _, c := range corrections {
+			p.logger.Info(ctx, "Glossary: cue %d %q -> %q", cues[i].Index, c.From, c.To)
+		}
+		cues[i].Text = text
+		total += len(corrections)
+	}
+
+	destPath := correctedPathFor(srtPath)
+	if err := subtitle.WriteSRTFile(destPath, cues); err != nil {
+		return "", fmt.Errorf("write corrected subtitle: %w", err)
+	}
+
+	p.logger.Info(ctx, "Glossary corrections: %d", total)
+	return destPath, nil
+}
+
+// correctedPathFor is the temp path of the glossary-corrected transcript
+func correctedPathFor(srtPath string) string {
+	return strings.TrimSuffix(srtPath, ".srt") + ".corrected.srt"
+}
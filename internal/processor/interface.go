@@ -6,3 +6,8 @@ import "context"
 type Processor interface {
 	Process(ctx context.Context, videoPath string) error
 }
+
+// Planner reports what Process would do for a video without running or writing anything
+type Planner interface {
+	Plan(ctx context.Context, videoPath string) (*Plan, error)
+}
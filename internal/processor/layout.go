@@ -0,0 +1,44 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// layoutSubtitles applies subtitles.layout to the transcript and writes the result next
+// to it, so the raw transcript stays available for a rerun with other limits
+func (p *implProcessor) layoutSubtitles(ctx context.Context, srtPath string) (string, error) {
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return "", fmt.Errorf("read SRT: %w", err)
+	}
+
+	laidOut := p.cfg.Subtitles.Layout.Layout().Apply(cues)
+	destPath := layoutPathFor(srtPath)
+	if err := subtitle.WriteSRTFile(destPath, laidOut); err != nil {
+		return "", fmt.Errorf("write laid out subtitle: %w", err)
+	}
+
+	p.logger.Info(ctx, "Subtitle layout applied: %d cues -> %d cues", len(cues), len(laidOut))
+	return destPath, nil
+}
+
+// rewrap wraps translated cues to the layout's line limits; translations do not keep
+// the original's line breaks. Timing is left alone so cues stay aligned with the transcript.
+func (p *implProcessor) rewrap(cues []subtitle.Cue) {
+	layout := p.cfg.Subtitles.Layout
+	if !layout.Enabled {
+		return
+	}
+	for i := range cues {
+		cues[i].Text = strings.Join(subtitle.Wrap(cues[i].Text, layout.MaxCharsPerLine, layout.Balance), "\n")
+	}
+}
+
+// layoutPathFor is the temp path of the laid out transcript
+func layoutPathFor(srtPath string) string {
+	return strings.TrimSuffix(srtPath, ".srt") + ".layout.srt"
+}
@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// muxTrack is one soft subtitle stream: an SRT file and its language code
+type muxTrack struct {
+	path     string
+	language string
+}
+
+// muxContainer returns the ffmpeg muxer, subtitle codec and output extension for a video
+// extension. Containers that cannot carry text subtitles are remuxed to Matroska.
+func muxContainer(ext, mkvCodec string) (format, codec, outExt string) {
+	switch strings.ToLower(ext) {
+	case ".mp4", ".m4v":
+		return "mp4", "mov_text", ext
+	case ".mov":
+		return "mov", "mov_text", ext
+	case ".webm":
+		return "webm", "webvtt", ext
+	case ".mkv":
+		return "matroska", mkvCodec, ext
+	default:
+		return "matroska", mkvCodec, ".mkv"
+	}
+}
+
+// muxArgs builds the ffmpeg command that stream-copies video and audio from input and adds
+// every subtitle file as a track tagged with its language; the first track is the default
+func muxArgs(input string, subtitles []string, languages []string, format, codec, output string) []string {
+	args := append(append([]string{}, progressArgs...), "-y", "-i", input)
+	for _, path := range subtitles {
+		args = append(args, "-i", path)
+	}
+	args = append(args, "-map", "0:v", "-map", "0:a?")
+	for i := range subtitles {
+		args = append(args, "-map", strconv.Itoa(i+1)+":0")
+	}
+	args = append(args, "-c:v", "copy", "-c:a", "copy", "-c:s", codec)
+	for i, lang := range languages {
+		args = append(args, fmt.Sprintf("-metadata:s:s:%d", i), "language="+subtitle.LanguageTag(lang))
+		disposition := "0"
+		if i == 0 {
+			disposition = "default"
+		}
+		args = append(args, fmt.Sprintf("-disposition:s:%d", i), disposition)
+	}
+	return append(args, "-f", format, output)
+}
+
+// muxTarget returns the muxer, subtitle codec, extension and final path of the mux output
+func (p *implProcessor) muxTarget(videoPath string, burned bool, settings jobSettings) (format, codec, outExt, outputPath string) {
+	if burned {
+		return "mp4", "mov_text", filepath.Ext(videoPath), videoPath
+	}
+	format, codec, outExt = muxContainer(filepath.Ext(videoPath), p.cfg.Subtitles.MKVCodec)
+	base := strings.TrimSuffix(filepath.Base(videoPath), filepath.Ext(videoPath))
+	return format, codec, outExt, filepath.Join(p.cfg.Paths.Output, "videos", settings.outDir, base+outExt)
+}
+
+// muxSubtitles adds tracks as soft subtitles to videoPath without re-encoding.
+// When burned is set, videoPath is the burned output (always MP4 content) and is replaced in
+// place; otherwise the result is written to videos/<outDir>/ in a container matching the source.
+func (p *implProcessor) muxSubtitles(ctx context.Context, videoPath string, burned bool, tracks []muxTrack, settings jobSettings) (string, error) {
+	format, codec, outExt, outputPath := p.muxTarget(videoPath, burned, settings)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return "", fmt.Errorf("create videos dir: %w", err)
+	}
+
+	p.logger.Info(ctx, "Muxing %d subtitle track(s) into %s (%s, stream copy)", len(tracks), filepath.Base(outputPath), codec)
+
+	tempDir, err := p.createTempDir("mux", videoPath)
+	if err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	subtitles := make([]string, len(tracks))
+	languages := make([]string, len(tracks))
+	for i, track := range tracks {
+		languages[i] = track.language
+		subtitles[i], err = filepath.Abs(track.path)
+		if err != nil {
+			return "", fmt.Errorf("resolve subtitle path: %w", err)
+		}
+		// ffmpeg's own SRT to ASS conversion ignores subtitle_style, so write styled scripts
+		if codec == "ass" {
+			cues, err := subtitle.ReadSRTFile(track.path)
+			if err != nil {
+				return "", fmt.Errorf("read subtitle track: %w", err)
+			}
+			subtitles[i] = filepath.Join(tempDir, fmt.Sprintf("track%d.ass", i))
+			if err := os.WriteFile(subtitles[i], []byte(subtitle.FormatASSWithStyle(cues, settings.style)), 0644); err != nil {
+				return "", fmt.Errorf("write styled subtitle track: %w", err)
+			}
+		}
+	}
+
+	absVideoPath, _ := filepath.Abs(videoPath)
+	tempOutput := filepath.Join(tempDir, "output"+outExt)
+	if err := p.executor.Stream(ctx, "", p.ffmpegProgress(ctx), "ffmpeg", muxArgs(absVideoPath, subtitles, languages, format, codec, tempOutput)...); err != nil {
+		return "", fmt.Errorf("ffmpeg mux: %w", err)
+	}
+
+	if err := os.Rename(tempOutput, outputPath); err != nil {
+		if err := p.copyFile(tempOutput, outputPath); err != nil {
+			return "", fmt.Errorf("move output to final location: %w", err)
+		}
+	}
+
+	p.logger.Info(ctx, "Subtitles muxed successfully: %s", outputPath)
+	return outputPath, nil
+}
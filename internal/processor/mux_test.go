@@ -0,0 +1,40 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMuxContainer(t *testing.T) {
+	tests := []struct {
+		ext, format, codec, outExt string
+	}{
+		{".mp4", "mp4", "mov_text", ".mp4"},
+		{".MOV", "mov", "mov_text", ".MOV"},
+		{".webm", "webm", "webvtt", ".webm"},
+		{".mkv", "matroska", "ass", ".mkv"},
+		{".avi", "matroska", "ass", ".mkv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			format, codec, outExt := muxContainer(tt.ext, "ass")
+			if format != tt.format || codec != tt.codec || outExt != tt.outExt {
+				t.Errorf("muxContainer(%q) = %s, %s, %s, want %s, %s, %s", tt.ext, format, codec, outExt, tt.format, tt.codec, tt.outExt)
+			}
+		})
+	}
+}
+
+func TestMuxArgs(t *testing.T) {
+	args := muxArgs("/in/video.mp4", []string{"/tmp/a.srt", "/out/a.vi.srt"}, []string{"en", "vi"}, "mp4", "mov_text", "/tmp/out.mp4")
+	got := strings.Join(args, " ")
+
+	want := "-progress pipe:1 -nostats -y -i /in/video.mp4 -i /tmp/a.srt -i /out/a.vi.srt -map 0:v -map 0:a? -map 1:0 -map 2:0 " +
+		"-c:v copy -c:a copy -c:s mov_text " +
+		"-metadata:s:s:0 language=eng -disposition:s:0 default -metadata:s:s:1 language=vie -disposition:s:1 0 " +
+		"-f mp4 /tmp/out.mp4"
+	if got != want {
+		t.Errorf("muxArgs() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,22 +1,96 @@
 package processor
 
 import (
+	"github.com/nguyentantai21042004/caption-flow/internal/cache"
+	"github.com/nguyentantai21042004/caption-flow/internal/chapters"
 	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/glossary"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
 	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+	"github.com/nguyentantai21042004/caption-flow/internal/progress"
+	"github.com/nguyentantai21042004/caption-flow/internal/transcriber"
+	"github.com/nguyentantai21042004/caption-flow/internal/translator"
 	"github.com/nguyentantai21042004/caption-flow/pkg/executor"
 )
 
 type implProcessor struct {
-	cfg      *config.Config
-	executor executor.Executor
-	logger   logger.Logger
+	cfg         *config.Config
+	executor    executor.Executor
+	transcriber transcriber.Transcriber
+	translator  translator.Translator // nil when translation is disabled
+	chapters    chapters.Generator    // nil when chapters are disabled
+	cache       cache.Cache           // nil when the transcript cache is disabled
+	glossary    *glossary.Glossary    // nil when no glossary is configured
+	store       jobstore.Store
+	progress    progress.Tracker
+	metrics     metrics.Metrics
+	logger      logger.Logger
 }
 
-// New creates a new Processor instance
-func New(cfg *config.Config, exec executor.Executor, log logger.Logger) Processor {
+// New creates a new Processor instance. tr, ch, tc and gl may be nil when translation,
+// chapters, the transcript cache or the glossary are disabled.
+func New(cfg *config.Config, exec executor.Executor, trans transcriber.Transcriber, tr translator.Translator, ch chapters.Generator, tc cache.Cache, gl *glossary.Glossary, store jobstore.Store, tracker progress.Tracker, m metrics.Metrics, log logger.Logger) Processor {
 	return &implProcessor{
+		cfg:         cfg,
+		executor:    exec,
+		transcriber: trans,
+		translator:  tr,
+		chapters:    ch,
+		cache:       tc,
+		glossary:    gl,
+		store:       store,
+		progress:    tracker,
+		metrics:     m,
+		logger:      log,
+	}
+}
+
+type implPlanner struct {
+	processor *implProcessor
+	recorder  executor.Recorder
+}
+
+// NewPlanner creates a Planner for -dry-run. It records commands instead of running them
+// and needs no job store, API keys or network access.
+func NewPlanner(cfg *config.Config) (Planner, error) {
+	var gl *glossary.Glossary
+	if cfg.Whisper.Glossary != "" {
+		var err error
+		if gl, err = glossary.Load(cfg.Whisper.Glossary); err != nil {
+			return nil, err
+		}
+	}
+
+	// Stages log as if they ran; keep the plan output clean
+	quiet := logger.NewWithFormat("error", cfg.Logging.Format)
+	rec := executor.NewRecorder()
+	proc := &implProcessor{
 		cfg:      cfg,
-		executor: exec,
-		logger:   log,
+		executor: rec,
+		glossary: gl,
+		progress: progress.New(),
+		metrics:  metrics.NewNop(),
+		logger:   quiet,
+	}
+	if cfg.Whisper.Backend == "" || cfg.Whisper.Backend == transcriber.BackendCLI {
+		trans, err := transcriber.New(cfg.Whisper, rec, quiet)
+		if err != nil {
+			return nil, err
+		}
+		proc.transcriber = trans
+	}
+	// Translator and chapters are set up as for a run so the same stages are planned. Plan
+	// never calls them, so they need no LLM client.
+	if cfg.Translation.Enabled {
+		proc.translator = translator.New(nil, cfg.Translation.BatchSize, quiet)
+	}
+	if cfg.Chapters.Enabled {
+		prompt, err := chapters.LoadPrompt(cfg.Gemini.Prompts)
+		if err != nil {
+			return nil, err
+		}
+		proc.chapters = chapters.New(nil, prompt, cfg.Chapters, quiet)
 	}
+	return &implPlanner{processor: proc, recorder: rec}, nil
 }
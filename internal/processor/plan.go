@@ -0,0 +1,225 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/transcriber"
+	"github.com/nguyentantai21042004/caption-flow/pkg/executor"
+)
+
+// Plan is what Process would do for one video, stage by stage
+type Plan struct {
+	VideoPath string
+	Steps     []PlanStep
+}
+
+// PlanStep is one pipeline stage of a Plan
+type PlanStep struct {
+	Stage    jobstore.Stage
+	Commands []executor.Command // external commands the stage would run
+	Actions  []string           // other effects: remote calls, files written or moved
+}
+
+// Plan walks the same stages as Process with a recording executor. It only reads the
+// config and the video's path, so nothing is executed, written or moved. Optional stages
+// are decided by optionalStagesFor, and paths and commands come from the helpers Process
+// uses, so the plan follows what a run does.
+func (pl *implPlanner) Plan(ctx context.Context, videoPath string) (*Plan, error) {
+	p := pl.processor
+	if _, err := os.Stat(videoPath); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{VideoPath: videoPath}
+	step := func(stage jobstore.Stage, actions ...string) {
+		plan.Steps = append(plan.Steps, PlanStep{Stage: stage, Commands: pl.recorder.Take(), Actions: actions})
+	}
+
+	settings := p.resolveSettings(videoPath, nil)
+	run := p.optionalStagesFor(settings)
+	originalFilename := filepath.Base(videoPath)
+	baseName := strings.TrimSuffix(originalFilename, filepath.Ext(originalFilename))
+
+	// Step 1: Extract audio (the duration probe runs first; its output is not parsed)
+	if _, err := p.executor.Execute(ctx, "ffprobe", probeDurationArgs(videoPath)...); err != nil {
+		return nil, fmt.Errorf("ffprobe duration: %w", err)
+	}
+	audioPath, err := p.extractAudio(ctx, videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("extract audio: %w", err)
+	}
+	step(jobstore.StageExtract, "write temp audio "+audioPath)
+
+	// Step 2: Transcribe
+	req := transcribeRequest(audioPath, settings)
+	req.OnOutput = p.whisperProgress(ctx)
+	srtPath := req.OutputPath
+	var remote string
+	switch p.cfg.Whisper.Backend {
+	case transcriber.BackendServer:
+		remote = fmt.Sprintf("POST %s to %s/inference", audioPath, strings.TrimRight(p.cfg.Whisper.ServerURL, "/"))
+	case transcriber.BackendOpenAI:
+		remote = fmt.Sprintf("POST %s to %s/audio/transcriptions (model %s)", audioPath, strings.TrimRight(p.cfg.Whisper.APIURL, "/"), p.cfg.Whisper.APIModel)
+	default:
+		if err := p.transcriber.Transcribe(ctx, req); err != nil {
+			return nil, fmt.Errorf("transcribe: %w", err)
+		}
+	}
+	var actions []string
+	if p.cfg.Cache.Enabled {
+		actions = append(actions, fmt.Sprintf("hash the %s and reuse a transcript cached in %s", p.cfg.Cache.Hash, p.cfg.Paths.Cache))
+	}
+	chunking := p.cfg.Whisper.Chunking
+	switch {
+	case chunking.Enabled:
+		actions = append(actions, fmt.Sprintf("if longer than %s: split on silence into chunks of up to %s, %d transcribed at a time",
+			chunking.MinDuration, chunking.ChunkDuration, chunking.Parallel))
+	case p.cfg.Whisper.Backend == transcriber.BackendOpenAI:
+		actions = append(actions, fmt.Sprintf("if longer than %s (upload limit): split on silence into chunks of up to %s, %d transcribed at a time",
+			config.OpenAIMaxAudio, chunking.ChunkDuration, chunking.Parallel))
+	}
+	if remote != "" {
+		actions = append(actions, remote)
+	}
+	step(jobstore.StageTranscribe, append(actions, "write temp subtitle "+srtPath)...)
+
+	// Step 3: Filter
+	if run.filter {
+		filter := p.cfg.Filter
+		actions := []string{fmt.Sprintf("drop %d hallucination phrases and runs of more than %d identical cues", len(filter.Phrases), filter.MaxRepeats)}
+		if filter.DropSilent {
+			if _, err := p.detectSilences(ctx, audioPath, filter.SilenceThreshold, filter.SilenceDuration); err != nil {
+				return nil, fmt.Errorf("filter subtitles: %w", err)
+			}
+			actions = append(actions, fmt.Sprintf("drop cues at least %.0f%% within silence", 100*filter.SilenceCoverage))
+		}
+		srtPath = filteredPathFor(srtPath)
+		step(jobstore.StageFilter, append(actions,
+			"write temp subtitle "+srtPath,
+			"write "+filepath.Join(p.cfg.Paths.Output, settings.outDir, baseName+".filtered.txt"))...)
+	}
+
+	// Step 4: Glossary
+	if run.glossary {
+		srtPath = correctedPathFor(srtPath)
+		step(jobstore.StageGlossary,
+			fmt.Sprintf("correct %d glossary terms from %s", len(p.glossary.Terms), p.cfg.Whisper.Glossary),
+			"write temp subtitle "+srtPath)
+	}
+
+	// Step 5: Layout
+	if run.layout {
+		layout := p.cfg.Subtitles.Layout
+		srtPath = layoutPathFor(srtPath)
+		step(jobstore.StageLayout,
+			fmt.Sprintf("reflow cues to %d lines of %d characters, %s-%s on screen", layout.MaxLines, layout.MaxCharsPerLine, layout.MinDuration, layout.MaxDuration),
+			"write temp subtitle "+srtPath)
+	}
+
+	// Step 6: Translate
+	burnPath := srtPath
+	tracks := []muxTrack{{path: srtPath, language: settings.language}}
+	if run.translate {
+		translationPath := p.translationPath(baseName, settings.outDir)
+		actions := []string{
+			fmt.Sprintf("translate cues to %s with %s %s, %d cues per request", p.cfg.Translation.Language, p.cfg.LLM.Provider, p.cfg.LLMModel(), p.cfg.Translation.BatchSize),
+			"write " + translationPath,
+		}
+		switch p.cfg.Translation.Burn {
+		case "translation":
+			burnPath = translationPath
+		case "bilingual":
+			burnPath = bilingualPathFor(srtPath)
+			actions = append(actions, "write temp bilingual subtitle "+burnPath)
+		}
+		tracks = append(tracks, muxTrack{path: translationPath, language: p.cfg.Translation.Language})
+		step(jobstore.StageTranslate, actions...)
+	}
+
+	// Step 7: Chapters
+	var metadataPath string
+	if run.chapters {
+		chapters := p.cfg.Chapters
+		actions := []string{
+			fmt.Sprintf("split the transcript into at most %d chapters of at least %s with %s %s", chapters.MaxChapters, chapters.MinLength, p.cfg.LLM.Provider, p.cfg.LLMModel()),
+			"write " + filepath.Join(p.cfg.Paths.Output, settings.outDir, baseName+".chapters.txt"),
+			"write " + filepath.Join(p.cfg.Paths.Output, settings.outDir, baseName+".chapters.vtt"),
+		}
+		if run.burn {
+			metadataPath = chaptersMetadataPathFor(srtPath)
+			actions = append(actions, "write temp chapter metadata "+metadataPath)
+		}
+		step(jobstore.StageChapters, actions...)
+	}
+
+	// Step 8: Burn
+	absVideoPath, _ := filepath.Abs(videoPath)
+	outputPath := ""
+	if run.burn {
+		outputPath = p.burnOutputPath(videoPath, settings)
+		tempDir, err := p.tempDirFor("burn", videoPath)
+		if err != nil {
+			return nil, fmt.Errorf("burn subtitle: %w", err)
+		}
+		absTempOutput, _ := filepath.Abs(filepath.Join(tempDir, "output.mp4"))
+		absMetadataPath := metadataPath
+		if metadataPath != "" {
+			absMetadataPath, _ = filepath.Abs(metadataPath)
+		}
+		if _, err := p.executor.ExecuteInDir(ctx, tempDir, "ffmpeg", p.burnArgs(absVideoPath, "subtitle.ass", absMetadataPath, absTempOutput)...); err != nil {
+			return nil, fmt.Errorf("burn subtitle: %w", err)
+		}
+		step(jobstore.StageBurn,
+			fmt.Sprintf("write styled %s from %s", filepath.Join(tempDir, "subtitle.ass"), burnPath),
+			"write "+outputPath)
+	}
+
+	// Step 9: Mux
+	if run.mux {
+		source := videoPath
+		if run.burn {
+			source = outputPath
+		}
+		format, codec, outExt, muxPath := p.muxTarget(source, run.burn, settings)
+		tempDir, err := p.tempDirFor("mux", source)
+		if err != nil {
+			return nil, fmt.Errorf("mux subtitles: %w", err)
+		}
+		source, _ = filepath.Abs(source)
+		subtitles := make([]string, len(tracks))
+		languages := make([]string, len(tracks))
+		for i, track := range tracks {
+			subtitles[i], _ = filepath.Abs(track.path)
+			if codec == "ass" {
+				subtitles[i] = filepath.Join(tempDir, fmt.Sprintf("track%d.ass", i))
+			}
+			languages[i] = track.language
+		}
+		if _, err := p.executor.Execute(ctx, "ffmpeg", muxArgs(source, subtitles, languages, format, codec, filepath.Join(tempDir, "output"+outExt))...); err != nil {
+			return nil, fmt.Errorf("mux subtitles: %w", err)
+		}
+		step(jobstore.StageMux, "write "+muxPath)
+	}
+
+	// Step 10: Export
+	formats, err := p.exportFormats()
+	if err != nil {
+		return nil, err
+	}
+	var writes []string
+	for _, format := range formats {
+		writes = append(writes, "write "+filepath.Join(p.cfg.Paths.Output, settings.outDir, baseName+format.Extension()))
+	}
+	step(jobstore.StageExport, writes...)
+
+	// Step 11: Archive
+	step(jobstore.StageArchive, fmt.Sprintf("move %s -> %s", videoPath, p.archivePath(videoPath, settings.relDir)))
+
+	return plan, nil
+}
@@ -0,0 +1,141 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Whisper: config.WhisperConfig{Backend: "cli", BinaryPath: "whisper-cli", ModelPath: "model.bin", Language: "en", Threads: 4},
+		FFmpeg:  config.FFmpegConfig{Encoder: "h264_videotoolbox", VideoBitrate: "5M", AudioCodec: "copy"},
+		Paths: config.PathsConfig{
+			Input:    filepath.Join(dir, "input"),
+			Output:   filepath.Join(dir, "output"),
+			Archived: filepath.Join(dir, "archived"),
+			Temp:     filepath.Join(dir, "temp"),
+		},
+		Subtitles: config.SubtitlesConfig{Formats: []string{"vtt"}, Mode: config.ModeBoth},
+	}
+	videoPath := filepath.Join(cfg.Paths.Input, "course", "intro.mp4")
+	if err := os.MkdirAll(filepath.Dir(videoPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(videoPath, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	planner, err := NewPlanner(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := planner.Plan(context.Background(), videoPath)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	jobID, _ := jobstore.IDFor(videoPath)
+	burnedID, _ := jobstore.IDFor(filepath.Join(cfg.Paths.Output, "videos", "course", "intro.mp4"))
+
+	var stages []jobstore.Stage
+	var all strings.Builder
+	for _, s := range plan.Steps {
+		stages = append(stages, s.Stage)
+		for _, c := range s.Commands {
+			all.WriteString(c.String() + "\n")
+		}
+		for _, a := range s.Actions {
+			all.WriteString(a + "\n")
+		}
+	}
+	wantStages := []jobstore.Stage{jobstore.StageExtract, jobstore.StageTranscribe, jobstore.StageBurn, jobstore.StageMux, jobstore.StageExport, jobstore.StageArchive}
+	if len(stages) != len(wantStages) {
+		t.Fatalf("stages = %v, want %v", stages, wantStages)
+	}
+	for i := range stages {
+		if stages[i] != wantStages[i] {
+			t.Errorf("stages = %v, want %v", stages, wantStages)
+			break
+		}
+	}
+
+	for _, want := range []string{
+		"ffprobe ",
+		"ffmpeg -progress pipe:1 -nostats -i " + videoPath + " -vn",
+		"whisper-cli -m model.bin -f " + filepath.Join(cfg.Paths.Input, "course", "intro_temp.wav"),
+		"-vf subtitles=subtitle.ass -c:v h264_videotoolbox",
+		"-c:s mov_text -metadata:s:s:0 language=eng",
+		"cd " + filepath.Join(cfg.Paths.Temp, "burn-"+jobID) + " && ffmpeg",
+		filepath.Join(cfg.Paths.Temp, "mux-"+burnedID, "output.mp4"),
+		"write " + filepath.Join(cfg.Paths.Output, "course", "intro.vtt"),
+		"move " + videoPath + " -> " + filepath.Join(cfg.Paths.Archived, "course", "intro.mp4"),
+	} {
+		if !strings.Contains(all.String(), want) {
+			t.Errorf("plan is missing %q:\n%s", want, all.String())
+		}
+	}
+
+	for _, d := range []string{cfg.Paths.Output, cfg.Paths.Archived, cfg.Paths.Temp} {
+		if _, err := os.Stat(d); !os.IsNotExist(err) {
+			t.Errorf("Plan() created %s", d)
+		}
+	}
+}
+
+func TestPlanOptionalStages(t *testing.T) {
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "input", "intro.mp4")
+	if err := os.MkdirAll(filepath.Dir(videoPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(videoPath, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Whisper:     config.WhisperConfig{Backend: "server", ServerURL: "http://127.0.0.1:8080", Language: "en"},
+		FFmpeg:      config.FFmpegConfig{Encoder: "libx264", VideoBitrate: "5M", AudioCodec: "copy"},
+		Gemini:      config.GeminiConfig{Prompts: filepath.Join("..", "..", "prompts")},
+		Paths:       config.PathsConfig{Input: filepath.Join(dir, "input"), Output: filepath.Join(dir, "output"), Archived: filepath.Join(dir, "archived"), Temp: filepath.Join(dir, "temp")},
+		Subtitles:   config.SubtitlesConfig{Mode: config.ModeMux},
+		Translation: config.TranslationConfig{Enabled: true, Language: "vi", Burn: "original"},
+		Chapters:    config.ChaptersConfig{Enabled: true, MinLength: 30 * time.Second, MaxChapters: 10},
+	}
+	planner, err := NewPlanner(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := planner.(*implPlanner).processor
+	if got, want := p.optionalStagesFor(p.resolveSettings(videoPath, nil)), (optionalStages{translate: true, chapters: true, mux: true}); got != want {
+		t.Errorf("optionalStagesFor() = %+v, want %+v", got, want)
+	}
+
+	plan, err := planner.Plan(context.Background(), videoPath)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	var stages []jobstore.Stage
+	for _, s := range plan.Steps {
+		stages = append(stages, s.Stage)
+		if s.Stage == jobstore.StageChapters {
+			for _, a := range s.Actions {
+				if strings.Contains(a, "metadata") {
+					t.Errorf("chapter metadata planned without a burn: %s", a)
+				}
+			}
+		}
+	}
+	want := []jobstore.Stage{jobstore.StageExtract, jobstore.StageTranscribe, jobstore.StageTranslate, jobstore.StageChapters, jobstore.StageMux, jobstore.StageExport, jobstore.StageArchive}
+	if fmt.Sprint(stages) != fmt.Sprint(want) {
+		t.Errorf("stages = %v, want %v", stages, want)
+	}
+}
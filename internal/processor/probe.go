@@ -0,0 +1,33 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// probeDurationArgs builds the ffprobe arguments that print the media duration in seconds
+func probeDurationArgs(mediaPath string) []string {
+	return []string{
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		mediaPath,
+	}
+}
+
+// probeDuration returns the media duration reported by ffprobe
+func (p *implProcessor) probeDuration(ctx context.Context, mediaPath string) (time.Duration, error) {
+	out, err := p.executor.Execute(ctx, "ffprobe", probeDurationArgs(mediaPath)...)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe duration: %w", err)
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(out), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse duration %q: %w", strings.TrimSpace(out), err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/cache"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
 )
 
-// Process orchestrates the entire video processing pipeline
-func (p *implProcessor) Process(ctx context.Context, videoPath string) error {
+// Process orchestrates the entire video processing pipeline.
+// Every stage is checkpointed in the job store, so a rerun after a crash
+// picks up at the last finished stage instead of starting over.
+func (p *implProcessor) Process(ctx context.Context, videoPath string) (err error) {
 	startTime := time.Now()
 	originalFilename := filepath.Base(videoPath)
 
@@ -16,43 +23,260 @@ func (p *implProcessor) Process(ctx context.Context, videoPath string) error {
 	p.logger.Info(ctx, "Starting video processing: %s", videoPath)
 	p.logger.Info(ctx, "========================================")
 
+	job, err := p.store.Open(ctx, videoPath)
+	if err != nil {
+		return fmt.Errorf("open job: %w", err)
+	}
+	ctx = logger.WithFields(ctx, "job", job.ID, "video", originalFilename)
+	p.logger.Info(ctx, "Job ID: %s", job.ID)
+
+	p.metrics.JobStarted()
+	defer p.metrics.JobFinished()
+
+	if job.Duration == 0 {
+		duration, err := p.probeDuration(ctx, videoPath)
+		if err != nil {
+			p.logger.Warn(ctx, "Failed to probe media duration: %v", err)
+		}
+		job.Duration = duration
+	}
+
+	settings := p.resolveSettings(videoPath, job)
+	if settings.relDir != "" {
+		p.logger.Info(ctx, "Subfolder: %s (language: %s, output: %s, burn: %v, mux: %v)",
+			settings.relDir, settings.language, settings.outDir, settings.burn, settings.mux)
+	}
+
+	run := p.optionalStagesFor(settings)
+
+	defer func() {
+		job.Finish(err)
+		p.saveJob(ctx, job)
+		p.progress.Clear(job.ID)
+	}()
+
 	// Step 1: Extract audio
-	audioPath, err := p.extractAudio(ctx, videoPath)
+	artifacts, err := p.runStage(ctx, job, jobstore.StageExtract, func(ctx context.Context) (map[string]string, error) {
+		audioPath, err := p.extractAudio(ctx, videoPath)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"audio": audioPath}, nil
+	})
 	if err != nil {
 		return fmt.Errorf("extract audio: %w", err)
 	}
-	defer p.cleanupTempFile(ctx, audioPath)
+	audioPath := artifacts["audio"]
+	if job.Duration == 0 {
+		// Without a probed duration, later media stages would get only the base timeout
+		if duration, err := wavDuration(audioPath); err == nil && duration > 0 {
+			p.logger.Info(ctx, "Using the extracted audio length as media duration: %s", duration.Round(time.Second))
+			job.Duration = duration
+		}
+	}
+
+	// Identical content transcribed with the same settings reuses the cached transcript
+	key, cached := p.transcriptKey(ctx, job, videoPath, audioPath, settings)
+	var entry *cache.Entry
+	if cached {
+		entry = p.cachedTranscript(ctx, key)
+	}
+	if entry != nil && p.isDuplicate(entry) {
+		job.DuplicateOf = entry.Source
+		p.logger.Info(ctx, "Duplicate of %s (output: %s), skipping", entry.Source, entry.Output)
+		if _, err := p.runStage(ctx, job, jobstore.StageArchive, func(ctx context.Context) (map[string]string, error) {
+			archivedPath, err := p.moveToArchived(ctx, videoPath, settings.relDir)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"video": archivedPath}, nil
+		}); err != nil {
+			p.logger.Warn(ctx, "Failed to move original to archived folder: %v", err)
+		}
+		p.cleanupTempFile(ctx, audioPath)
+		return nil
+	}
 
 	// Step 2: Transcribe audio to subtitle
-	srtPath, err := p.transcribe(ctx, audioPath)
+	artifacts, err = p.runStage(ctx, job, jobstore.StageTranscribe, func(ctx context.Context) (map[string]string, error) {
+		if entry != nil {
+			srtPath, err := p.restoreTranscript(ctx, entry, audioPath, settings)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"srt": srtPath}, nil
+		}
+		srtPath, err := p.transcribe(ctx, audioPath, settings)
+		if err != nil {
+			return nil, err
+		}
+		if cached {
+			p.storeTranscript(ctx, key, srtPath, videoPath)
+		}
+		return map[string]string{"srt": srtPath}, nil
+	})
 	if err != nil {
 		return fmt.Errorf("transcribe: %w", err)
 	}
-	defer p.cleanupTempFile(ctx, srtPath)
+	srtPath := artifacts["srt"]
+	baseName := strings.TrimSuffix(originalFilename, filepath.Ext(originalFilename))
+	tempFiles := []string{audioPath, srtPath}
 
-	// Step 3: Burn subtitle into video (keeps original filename)
-	outputPath, err := p.burnSubtitle(ctx, videoPath, srtPath)
-	if err != nil {
-		return fmt.Errorf("burn subtitle: %w", err)
+	// Step 3: Drop whisper hallucinations (optional)
+	if run.filter {
+		artifacts, err = p.runStage(ctx, job, jobstore.StageFilter, func(ctx context.Context) (map[string]string, error) {
+			return p.filterSubtitles(ctx, srtPath, audioPath, baseName, settings.outDir)
+		})
+		if err != nil {
+			return fmt.Errorf("filter subtitles: %w", err)
+		}
+		srtPath = artifacts["srt"]
+		tempFiles = append(tempFiles, srtPath)
 	}
 
-	// Step 4: Copy SRT to output folder (with original name)
-	srtOutputPath := filepath.Join(p.cfg.Paths.Output, originalFilename[:len(originalFilename)-len(filepath.Ext(originalFilename))]+".srt")
-	if err := p.copySRT(ctx, srtPath, srtOutputPath); err != nil {
-		p.logger.Warn(ctx, "Failed to copy SRT to output: %v", err)
+	// Step 4: Correct terms from the glossary (optional)
+	if run.glossary {
+		artifacts, err = p.runStage(ctx, job, jobstore.StageGlossary, func(ctx context.Context) (map[string]string, error) {
+			correctedPath, err := p.correctSubtitles(ctx, srtPath)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"srt": correctedPath}, nil
+		})
+		if err != nil {
+			return fmt.Errorf("glossary: %w", err)
+		}
+		srtPath = artifacts["srt"]
+		tempFiles = append(tempFiles, srtPath)
 	}
 
-	// Step 5: Move original video to archived folder
-	if err := p.moveToArchived(ctx, videoPath); err != nil {
+	// Step 5: Reflow the transcript for readability (optional)
+	if run.layout {
+		artifacts, err = p.runStage(ctx, job, jobstore.StageLayout, func(ctx context.Context) (map[string]string, error) {
+			laidOutPath, err := p.layoutSubtitles(ctx, srtPath)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"srt": laidOutPath}, nil
+		})
+		if err != nil {
+			return fmt.Errorf("layout subtitles: %w", err)
+		}
+		srtPath = artifacts["srt"]
+		tempFiles = append(tempFiles, srtPath)
+	}
+
+	// Step 6: Translate subtitle (optional), choosing the track to burn
+	burnPath := srtPath
+	tracks := []muxTrack{{path: srtPath, language: settings.language}}
+	var bilingualPath string
+	if run.translate {
+		artifacts, err = p.runStage(ctx, job, jobstore.StageTranslate, func(ctx context.Context) (map[string]string, error) {
+			return p.translateSubtitles(ctx, srtPath, baseName, settings.outDir)
+		})
+		if err != nil {
+			return fmt.Errorf("translate: %w", err)
+		}
+		bilingualPath = artifacts["bilingual"]
+		if bilingualPath != "" {
+			tempFiles = append(tempFiles, bilingualPath)
+		}
+		tracks = append(tracks, muxTrack{path: artifacts["translation"], language: p.cfg.Translation.Language})
+		switch p.cfg.Translation.Burn {
+		case "translation":
+			burnPath = artifacts["translation"]
+		case "bilingual":
+			burnPath = bilingualPath
+		}
+	}
+
+	// Step 7: Generate chapters (optional), embedded by the burn
+	var metadataPath string
+	if run.chapters {
+		artifacts, err = p.runStage(ctx, job, jobstore.StageChapters, func(ctx context.Context) (map[string]string, error) {
+			return p.generateChapters(ctx, srtPath, baseName, settings.outDir, job.Duration, run.burn)
+		})
+		if err != nil {
+			return fmt.Errorf("generate chapters: %w", err)
+		}
+		if metadataPath = artifacts["metadata"]; metadataPath != "" {
+			tempFiles = append(tempFiles, metadataPath)
+		}
+	}
+
+	// Step 8: Burn subtitle into video (keeps original filename)
+	outputPath := "(not burned)"
+	if run.burn {
+		artifacts, err = p.runStage(ctx, job, jobstore.StageBurn, func(ctx context.Context) (map[string]string, error) {
+			outputPath, err := p.burnSubtitle(ctx, videoPath, burnPath, metadataPath, settings)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"video": outputPath}, nil
+		})
+		if err != nil {
+			return fmt.Errorf("burn subtitle: %w", err)
+		}
+		outputPath = artifacts["video"]
+	}
+
+	// Step 9: Mux subtitles as soft tracks (stream copy, one track per language)
+	if run.mux {
+		source := videoPath
+		if run.burn {
+			source = outputPath
+		}
+		artifacts, err = p.runStage(ctx, job, jobstore.StageMux, func(ctx context.Context) (map[string]string, error) {
+			outputPath, err := p.muxSubtitles(ctx, source, run.burn, tracks, settings)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"video": outputPath}, nil
+		})
+		if err != nil {
+			return fmt.Errorf("mux subtitles: %w", err)
+		}
+		outputPath = artifacts["video"]
+	}
+
+	// Step 10: Export subtitles to output folder (SRT + configured formats, original name)
+	srtOutputPath := filepath.Join(p.cfg.Paths.Output, settings.outDir, baseName+".srt")
+	if _, err := p.runStage(ctx, job, jobstore.StageExport, func(ctx context.Context) (map[string]string, error) {
+		return p.exportSubtitles(ctx, srtPath, baseName, settings.outDir)
+	}); err != nil {
+		p.logger.Warn(ctx, "Failed to export subtitles to output: %v", err)
+	}
+
+	// Step 11: Move original video to archived folder
+	if _, err := p.runStage(ctx, job, jobstore.StageArchive, func(ctx context.Context) (map[string]string, error) {
+		archivedPath, err := p.moveToArchived(ctx, videoPath, settings.relDir)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"video": archivedPath}, nil
+	}); err != nil {
 		p.logger.Warn(ctx, "Failed to move original to archived folder: %v", err)
 	}
 
+	if cached {
+		output := outputPath
+		if !settings.burn && !settings.mux {
+			output = srtOutputPath
+		}
+		p.recordOutput(ctx, key, output)
+	}
+
+	// Temp files are kept until the job succeeds so a failed run can resume from them
+	for _, path := range tempFiles {
+		p.cleanupTempFile(ctx, path)
+	}
+
 	duration := time.Since(startTime)
 	p.logger.Info(ctx, "========================================")
 	p.logger.Info(ctx, "Processing completed successfully!")
 	p.logger.Info(ctx, "Output video: %s", outputPath)
 	p.logger.Info(ctx, "Output subtitle: %s", srtOutputPath)
-	p.logger.Info(ctx, "Processing time: %s", duration)
+	p.logger.Info(logger.WithFields(ctx, "duration_ms", duration.Milliseconds()), "Processing time: %s", duration)
 	p.logger.Info(ctx, "========================================")
 
 	return nil
@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+// progressArgs make ffmpeg write machine-readable progress to stdout instead of the stats line
+var progressArgs = []string{"-progress", "pipe:1", "-nostats"}
+
+// reWhisperProgress matches whisper.cpp's --print-progress output,
+// e.g. "whisper_print_progress_callback: progress =  35%"
+var reWhisperProgress = regexp.MustCompile(`progress\s*=\s*(\d+(?:\.\d+)?)%`)
+
+type stageKey struct{}
+
+// stageInfo identifies the running stage for progress reports
+type stageInfo struct {
+	jobID    string
+	stage    jobstore.Stage
+	duration time.Duration // media duration, 0 when unknown
+}
+
+func withStage(ctx context.Context, job *jobstore.Job, stage jobstore.Stage) context.Context {
+	return context.WithValue(ctx, stageKey{}, stageInfo{jobID: job.ID, stage: stage, duration: job.Duration})
+}
+
+// reportProgress records percent for the stage running in ctx and logs every 10% step
+func (p *implProcessor) reportProgress(ctx context.Context, percent float64) {
+	info, ok := ctx.Value(stageKey{}).(stageInfo)
+	if !ok {
+		return
+	}
+
+	prev, cur := p.progress.Update(info.jobID, string(info.stage), percent)
+	if prev.Stage == cur.Stage && int(prev.Percent/10) == int(cur.Percent/10) {
+		return
+	}
+	if cur.ETA > 0 {
+		p.logger.Info(ctx, "%s: %.0f%% (ETA %s)", info.stage, cur.Percent, cur.ETA.Round(time.Second))
+	} else {
+		p.logger.Info(ctx, "%s: %.0f%%", info.stage, cur.Percent)
+	}
+}
+
+// ffmpegProgress returns an output callback for commands run with progressArgs. Percentages
+// need the media duration, so nothing is reported when it is unknown.
+func (p *implProcessor) ffmpegProgress(ctx context.Context) func(line string) {
+	info, _ := ctx.Value(stageKey{}).(stageInfo)
+	return func(line string) {
+		if percent, ok := parseFFmpegProgress(line, info.duration); ok {
+			p.reportProgress(ctx, percent)
+		}
+	}
+}
+
+// whisperProgress returns an output callback for whisper.cpp run with --print-progress
+func (p *implProcessor) whisperProgress(ctx context.Context) func(line string) {
+	return func(line string) {
+		if percent, ok := parseWhisperProgress(line); ok {
+			p.reportProgress(ctx, percent)
+		}
+	}
+}
+
+// parseFFmpegProgress turns an ffmpeg -progress line into a percentage of total
+func parseFFmpegProgress(line string, total time.Duration) (float64, bool) {
+	key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+	if !ok {
+		return 0, false
+	}
+	switch key {
+	case "progress":
+		return 100, value == "end"
+	case "out_time_us":
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || total <= 0 || us < 0 {
+			return 0, false
+		}
+		return min(100*float64(time.Duration(us)*time.Microsecond)/float64(total), 100), true
+	}
+	return 0, false
+}
+
+// parseWhisperProgress extracts the percentage from a whisper.cpp progress line
+func parseWhisperProgress(line string) (float64, bool) {
+	m := reWhisperProgress.FindStringSubmatch(line)
+	if m == nil {
+		return 0, false
+	}
+	percent, err := strconv.ParseFloat(m[1], 64)
+	return percent, err == nil
+}
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFFmpegProgress(t *testing.T) {
+	tests := []struct {
+		line   string
+		total  time.Duration
+		want   float64
+		wantOK bool
+	}{
+		{"out_time_us=30000000", time.Minute, 50, true},
+		{"out_time_us=N/A", time.Minute, 0, false},
+		{"out_time_us=30000000", 0, 0, false},
+		{"out_time_us=90000000", time.Minute, 100, true},
+		{"progress=end", 0, 100, true},
+		{"progress=continue", time.Minute, 100, false},
+		{"frame=120", time.Minute, 0, false},
+		{"Stream mapping:", time.Minute, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := parseFFmpegProgress(tt.line, tt.total)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("parseFFmpegProgress(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseWhisperProgress(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   float64
+		wantOK bool
+	}{
+		{"whisper_print_progress_callback: progress =  35%", 35, true},
+		{"progress = 100%", 100, true},
+		{"[00:00:00.000 --> 00:00:02.000]  Hello", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseWhisperProgress(tt.line)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseWhisperProgress(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// jobSettings are the per-video settings after applying the matching route
+type jobSettings struct {
+	relDir   string // input subfolder relative to paths.input ("" for the root)
+	outDir   string // subfolder used below the output and videos folders
+	language string
+	prompt   string
+	burn     bool           // burn captions into the video (re-encode)
+	mux      bool           // add captions as soft subtitle tracks (stream copy)
+	style    subtitle.Style // burned-in caption style
+}
+
+// resolveSettings derives the settings for videoPath from the global config, the first
+// route matching its subfolder and the job's own overrides (job may be nil). Output
+// mirrors the input tree by default.
+func (p *implProcessor) resolveSettings(videoPath string, job *jobstore.Job) jobSettings {
+	settings := jobSettings{
+		language: p.cfg.Whisper.Language,
+		prompt:   p.cfg.Whisper.Prompt,
+	}
+	settings.setMode(p.cfg.Subtitles.Mode)
+
+	absInput, errIn := filepath.Abs(p.cfg.Paths.Input)
+	absDir, errDir := filepath.Abs(filepath.Dir(videoPath))
+	if errIn == nil && errDir == nil {
+		if rel, err := filepath.Rel(absInput, absDir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			settings.relDir = rel
+		}
+	}
+	settings.outDir = settings.relDir
+
+	// Styles are checked by config.Validate and the job API, so conversion errors cannot happen here
+	route, ok := p.cfg.MatchRoute(filepath.ToSlash(settings.relDir))
+	style := p.cfg.SubtitleStyle.Merge(route.Style)
+	if ok {
+		settings.applyRoute(route)
+	}
+	if job != nil {
+		if job.Style != nil {
+			style = style.Merge(*job.Style)
+		}
+		if job.Mode != "" {
+			settings.setMode(job.Mode)
+		}
+	}
+	settings.style, _ = style.ASSStyle()
+
+	// Glossary terms prime whisper with the right spellings
+	settings.prompt = p.glossary.Prompt(settings.prompt)
+	return settings
+}
+
+// applyRoute overrides the global settings with those the route sets
+func (s *jobSettings) applyRoute(route config.RouteConfig) {
+	if route.Language != "" {
+		s.language = route.Language
+	}
+	if route.Prompt != "" {
+		s.prompt = route.Prompt
+	}
+	if route.Output != "" {
+		s.outDir = filepath.FromSlash(route.Output)
+	}
+	if route.Mode != "" {
+		s.setMode(route.Mode)
+	}
+}
+
+// setMode sets burn/mux from a subtitles.mode value
+func (s *jobSettings) setMode(mode string) {
+	s.burn = mode == config.ModeBurn || mode == config.ModeBoth
+	s.mux = mode == config.ModeMux || mode == config.ModeBoth
+}
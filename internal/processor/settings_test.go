@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+func TestResolveSettings(t *testing.T) {
+	cfg := &config.Config{
+		Paths:         config.PathsConfig{Input: "input"},
+		Subtitles:     config.SubtitlesConfig{Mode: config.ModeBoth},
+		SubtitleStyle: config.SubtitleStyleConfig{FontFamily: "Arial", FontSize: 20},
+		Routes: []config.RouteConfig{
+			{Match: "clients/acme", Mode: config.ModeBurn, Style: config.SubtitleStyleConfig{FontFamily: "Roboto"}},
+		},
+	}
+	p := &implProcessor{cfg: cfg}
+	videoPath := filepath.Join("input", "clients", "acme", "intro.mp4")
+
+	tests := []struct {
+		name     string
+		job      *jobstore.Job
+		wantBurn bool
+		wantMux  bool
+		wantFont string
+		wantSize float64
+	}{
+		{"route only", nil, true, false, "Roboto", 20},
+		{"job without overrides", &jobstore.Job{}, true, false, "Roboto", 20},
+		{"job overrides route", &jobstore.Job{Mode: config.ModeMux, Style: &config.SubtitleStyleConfig{FontSize: 32}}, false, true, "Roboto", 32},
+		{"job style font", &jobstore.Job{Style: &config.SubtitleStyleConfig{FontFamily: "Inter"}}, true, false, "Inter", 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.resolveSettings(videoPath, tt.job)
+			if got.burn != tt.wantBurn || got.mux != tt.wantMux {
+				t.Errorf("burn, mux = %v, %v, want %v, %v", got.burn, got.mux, tt.wantBurn, tt.wantMux)
+			}
+			if got.style.FontName != tt.wantFont || got.style.FontSize != tt.wantSize {
+				t.Errorf("font = %s %v, want %s %v", got.style.FontName, got.style.FontSize, tt.wantFont, tt.wantSize)
+			}
+		})
+	}
+}
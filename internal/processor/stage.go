@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/pkg/executor"
+)
+
+// stageFunc runs one pipeline stage and returns the files it produced, keyed by role.
+// ctx carries the job and stage log fields.
+type stageFunc func(ctx context.Context) (map[string]string, error)
+
+// mediaStages are the stages whose cost scales with media length; only these get a realtime factor
+var mediaStages = map[jobstore.Stage]bool{
+	jobstore.StageExtract:    true,
+	jobstore.StageTranscribe: true,
+	jobstore.StageBurn:       true,
+	jobstore.StageMux:        true,
+}
+
+// optionalStages are the stages a job runs beyond extract, transcribe, export and archive.
+// Process and Plan both follow them, so a dry run cannot list a stage a run would skip.
+type optionalStages struct {
+	filter    bool
+	glossary  bool
+	layout    bool
+	translate bool
+	chapters  bool
+	burn      bool
+	mux       bool
+}
+
+// optionalStagesFor decides the optional stages from the config, the collaborators New
+// was given and the job's settings
+func (p *implProcessor) optionalStagesFor(settings jobSettings) optionalStages {
+	return optionalStages{
+		filter:    p.cfg.Filter.Enabled,
+		glossary:  p.glossary != nil,
+		layout:    p.cfg.Subtitles.Layout.Enabled,
+		translate: p.translator != nil && p.cfg.Translation.Enabled,
+		chapters:  p.chapters != nil && p.cfg.Chapters.Enabled,
+		burn:      settings.burn,
+		mux:       settings.mux,
+	}
+}
+
+// commandTimeout is the limit for each command of stage: executor.timeout plus, for
+// media stages, executor.timeout_factor times the media duration. 0 means no limit, which
+// media stages also get while the duration is unknown rather than the bare base timeout.
+func (p *implProcessor) commandTimeout(stage jobstore.Stage, duration time.Duration) time.Duration {
+	timeout := p.cfg.Executor.Timeout
+	if mediaStages[stage] && p.cfg.Executor.TimeoutFactor > 0 {
+		if duration <= 0 {
+			return 0
+		}
+		timeout += time.Duration(p.cfg.Executor.TimeoutFactor * float64(duration))
+	}
+	return timeout
+}
+
+// runStage executes fn unless the job already holds a completed checkpoint for stage
+// whose artifacts are still on disk, in which case the recorded artifacts are reused
+func (p *implProcessor) runStage(ctx context.Context, job *jobstore.Job, stage jobstore.Stage, fn stageFunc) (map[string]string, error) {
+	ctx = logger.WithFields(ctx, "stage", string(stage))
+	ctx = withStage(ctx, job, stage)
+	ctx = executor.WithTimeout(ctx, p.commandTimeout(stage, job.Duration))
+
+	if rec, ok := job.Completed(stage); ok && artifactsExist(rec.Artifacts) {
+		p.logger.Info(ctx, "Resuming: stage %s already completed at %s", stage, rec.CompletedAt.Format(time.RFC3339))
+		return rec.Artifacts, nil
+	}
+
+	job.StartStage(stage)
+	p.saveJob(ctx, job)
+
+	started := time.Now()
+	artifacts, err := fn(ctx)
+	elapsed := time.Since(started)
+
+	var mediaDuration time.Duration
+	if mediaStages[stage] {
+		mediaDuration = job.Duration
+	}
+	p.metrics.ObserveStage(string(stage), elapsed, mediaDuration, err)
+	p.logger.Debug(logger.WithFields(ctx, "duration_ms", elapsed.Milliseconds()), "Stage %s finished in %s", stage, elapsed.Round(time.Millisecond))
+
+	if err != nil {
+		job.FailStage(stage, err)
+		p.saveJob(ctx, job)
+		return nil, err
+	}
+
+	job.CompleteStage(stage, artifacts)
+	if err := p.store.Save(ctx, job); err != nil {
+		return nil, fmt.Errorf("save %s checkpoint: %w", stage, err)
+	}
+	return artifacts, nil
+}
+
+// saveJob persists job state, logging instead of failing the pipeline on store errors
+func (p *implProcessor) saveJob(ctx context.Context, job *jobstore.Job) {
+	if err := p.store.Save(ctx, job); err != nil {
+		p.logger.Warn(ctx, "Failed to save job %s: %v", job.ID, err)
+	}
+}
+
+// artifactsExist reports whether every checkpointed file is still present
+func artifactsExist(artifacts map[string]string) bool {
+	for _, path := range artifacts {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+)
+
+func TestCommandTimeout(t *testing.T) {
+	p := &implProcessor{cfg: &config.Config{Executor: config.ExecutorConfig{Timeout: 10 * time.Minute, TimeoutFactor: 3}}}
+
+	tests := []struct {
+		name     string
+		stage    jobstore.Stage
+		duration time.Duration
+		want     time.Duration
+	}{
+		{"media stage", jobstore.StageBurn, 40 * time.Minute, 2*time.Hour + 10*time.Minute},
+		{"media stage without duration", jobstore.StageTranscribe, 0, 0},
+		{"other stage", jobstore.StageExport, 40 * time.Minute, 10 * time.Minute},
+		{"other stage without duration", jobstore.StageExport, 0, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.commandTimeout(tt.stage, tt.duration); got != tt.want {
+				t.Errorf("commandTimeout(%s, %s) = %s, want %s", tt.stage, tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWavDuration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audio.wav")
+	if err := os.WriteFile(path, make([]byte, wavHeaderSize+90*wavBytesPerSecond), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := wavDuration(path); err != nil || got != 90*time.Second {
+		t.Errorf("wavDuration() = %s, %v; want 1m30s", got, err)
+	}
+}
@@ -6,36 +6,28 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
 )
 
 // burnSubtitle burns subtitle into video using hardware acceleration
-// Uses relative path with working directory to avoid FFmpeg filter parsing issues
-func (p *implProcessor) burnSubtitle(ctx context.Context, videoPath, srtPath string) (string, error) {
-	filename := filepath.Base(videoPath)
-	videosDir := filepath.Join(p.cfg.Paths.Output, "videos")
-	if err := os.MkdirAll(videosDir, 0755); err != nil {
+// Uses relative path with working directory to avoid FFmpeg filter parsing issues.
+// A non-empty metadataPath is an ffmetadata file whose chapters are embedded in the same pass.
+func (p *implProcessor) burnSubtitle(ctx context.Context, videoPath, srtPath, metadataPath string, settings jobSettings) (string, error) {
+	outputPath := p.burnOutputPath(videoPath, settings)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return "", fmt.Errorf("create videos dir: %w", err)
 	}
-	outputPath := filepath.Join(videosDir, filename)
 
 	p.logger.Info(ctx, "Burning subtitle into video (M4 Pro optimized): %s", videoPath)
 
-	// Convert SRT to ASS first for better styling
-	assPath := srtPath[:len(srtPath)-4] + ".ass"
-	argsConvert := []string{
-		"-i", srtPath,
-		"-y",
-		assPath,
-	}
-
-	if _, err := p.executor.Execute(ctx, "ffmpeg", argsConvert...); err != nil {
-		p.logger.Warn(ctx, "Failed to convert SRT to ASS, using SRT: %v", err)
-		assPath = srtPath // Use SRT if conversion fails
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return "", fmt.Errorf("read subtitle: %w", err)
 	}
-	defer os.Remove(assPath)
 
 	// Create isolated temp dir per video to avoid race conditions
-	tempDir, err := os.MkdirTemp(p.cfg.Paths.Temp, "burn-*")
+	tempDir, err := p.createTempDir("burn", videoPath)
 	if err != nil {
 		return "", fmt.Errorf("create temp dir: %w", err)
 	}
@@ -44,14 +36,17 @@ func (p *implProcessor) burnSubtitle(ctx context.Context, videoPath, srtPath str
 	tempSubtitle := filepath.Join(tempDir, "subtitle.ass")
 	tempOutput := filepath.Join(tempDir, "output.mp4")
 
-	// Copy subtitle to temp location
-	if err := p.copyFile(assPath, tempSubtitle); err != nil {
-		return "", fmt.Errorf("copy subtitle to temp: %w", err)
+	// Write the ASS script with the configured style next to the output
+	if err := os.WriteFile(tempSubtitle, []byte(subtitle.FormatASSWithStyle(cues, settings.style)), 0644); err != nil {
+		return "", fmt.Errorf("write styled subtitle: %w", err)
 	}
 
 	// Get absolute paths for input/output
 	absVideoPath, _ := filepath.Abs(videoPath)
 	absTempOutput, _ := filepath.Abs(tempOutput)
+	if metadataPath != "" {
+		metadataPath, _ = filepath.Abs(metadataPath)
+	}
 
 	workDir := tempDir
 	subFilename := filepath.Base(tempSubtitle)
@@ -59,24 +54,15 @@ func (p *implProcessor) burnSubtitle(ctx context.Context, videoPath, srtPath str
 	// Clean filename (trim spaces)
 	subFilename = strings.TrimSpace(subFilename)
 
-	// Use subtitles filter with RELATIVE path (no quotes needed!)
-	args := []string{
-		"-y",
-		"-i", absVideoPath,
-		"-vf", fmt.Sprintf("subtitles=%s", subFilename), // No quotes!
-		"-c:v", p.cfg.FFmpeg.Encoder,
-		"-b:v", p.cfg.FFmpeg.VideoBitrate,
-		"-c:a", p.cfg.FFmpeg.AudioCodec,
-		absTempOutput,
-	}
+	args := p.burnArgs(absVideoPath, subFilename, metadataPath, absTempOutput)
 
 	p.logger.Debug(ctx, "FFmpeg command in dir %s: ffmpeg -vf subtitles=%s ...", workDir, subFilename)
 
 	// Execute FFmpeg in the temp directory (this is the key!)
-	if _, err := p.executor.ExecuteInDir(ctx, workDir, "ffmpeg", args...); err != nil {
+	if err := p.executor.Stream(ctx, workDir, p.ffmpegProgress(ctx), "ffmpeg", args...); err != nil {
 		// If hardware encoder fails, try software encoder
 		p.logger.Warn(ctx, "Hardware encoder failed, trying software encoder...")
-		if err := p.burnSubtitleSoftware(ctx, workDir, absVideoPath, subFilename, absTempOutput); err != nil {
+		if err := p.burnSubtitleSoftware(ctx, workDir, absVideoPath, subFilename, metadataPath, absTempOutput); err != nil {
 			return "", fmt.Errorf("both hardware and software encoders failed: %w", err)
 		}
 	}
@@ -93,6 +79,33 @@ func (p *implProcessor) burnSubtitle(ctx context.Context, videoPath, srtPath str
 	return outputPath, nil
 }
 
+// burnOutputPath is where the burned video is written: videos/<outDir>/<original filename>
+func (p *implProcessor) burnOutputPath(videoPath string, settings jobSettings) string {
+	return filepath.Join(p.cfg.Paths.Output, "videos", settings.outDir, filepath.Base(videoPath))
+}
+
+// burnArgs builds the hardware encoder command. Uses the subtitles filter with a
+// RELATIVE path (no quotes needed!), so it must run in the subtitle's directory.
+func (p *implProcessor) burnArgs(videoPath, subFilename, metadataPath, outputPath string) []string {
+	return append(burnInputs(videoPath, metadataPath),
+		"-vf", fmt.Sprintf("subtitles=%s", subFilename), // No quotes!
+		"-c:v", p.cfg.FFmpeg.Encoder,
+		"-b:v", p.cfg.FFmpeg.VideoBitrate,
+		"-c:a", p.cfg.FFmpeg.AudioCodec,
+		outputPath,
+	)
+}
+
+// burnInputs starts a burn command: the video input, then the ffmetadata file whose
+// chapters are embedded when metadataPath is set
+func burnInputs(videoPath, metadataPath string) []string {
+	args := append(append([]string{}, progressArgs...), "-y", "-i", videoPath)
+	if metadataPath != "" {
+		args = append(args, "-i", metadataPath, "-map_chapters", "1")
+	}
+	return args
+}
+
 // copyFile copies a file from src to dst
 func (p *implProcessor) copyFile(src, dst string) error {
 	data, err := os.ReadFile(src)
@@ -106,19 +119,17 @@ func (p *implProcessor) copyFile(src, dst string) error {
 }
 
 // burnSubtitleSoftware uses software encoder as fallback
-func (p *implProcessor) burnSubtitleSoftware(ctx context.Context, workDir, videoPath, subFilename, outputPath string) error {
-	args := []string{
-		"-y",
-		"-i", videoPath,
+func (p *implProcessor) burnSubtitleSoftware(ctx context.Context, workDir, videoPath, subFilename, metadataPath, outputPath string) error {
+	args := append(burnInputs(videoPath, metadataPath),
 		"-vf", fmt.Sprintf("subtitles=%s", subFilename), // No quotes!
 		"-c:v", "libx264",
 		"-preset", p.cfg.FFmpeg.Preset,
 		"-crf", "23",
 		"-c:a", "copy",
 		outputPath,
-	}
+	)
 
-	if _, err := p.executor.ExecuteInDir(ctx, workDir, "ffmpeg", args...); err != nil {
+	if err := p.executor.Stream(ctx, workDir, p.ffmpegProgress(ctx), "ffmpeg", args...); err != nil {
 		return fmt.Errorf("software encoder failed: %w", err)
 	}
 
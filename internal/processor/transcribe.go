@@ -4,54 +4,45 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
-	"strconv"
 	"strings"
-)
 
-// transcribe uses Whisper to convert audio to subtitle file (SRT format)
-// Optimized for M4 Pro with Metal acceleration and multi-threading
-func (p *implProcessor) transcribe(ctx context.Context, audioPath string) (string, error) {
-	// Generate output prefix (Whisper will append .srt)
-	outputPrefix := strings.TrimSuffix(audioPath, filepath.Ext(audioPath))
+	"github.com/nguyentantai21042004/caption-flow/internal/jobstore"
+	"github.com/nguyentantai21042004/caption-flow/internal/transcriber"
+)
 
-	p.logger.Info(ctx, "Starting transcription with %d threads (Metal GPU enabled): %s",
-		p.cfg.Whisper.Threads, audioPath)
+// transcribe converts audio to a subtitle file (SRT format) using the configured backend
+func (p *implProcessor) transcribe(ctx context.Context, audioPath string, settings jobSettings) (string, error) {
+	req := transcribeRequest(audioPath, settings)
+	req.OnOutput = p.whisperProgress(ctx)
 
-	// Whisper arguments optimized for M4 Pro
-	// -m: Model path
-	// -f: Input audio file
-	// -osrt: Output SRT format
-	// -l: Force language (prevents hallucination)
-	// --prompt: Domain-specific keywords to improve accuracy
-	// --output-file: Output file prefix
-	// -t: Number of threads (8 for M4 Pro)
-	// -ml: Max segment length (0 = no limit, better for long videos)
-	// -mc: Max context (0 = no limit)
-	// -bo: Best of (5 = better accuracy)
-	args := []string{
-		"-m", p.cfg.Whisper.ModelPath,
-		"-f", audioPath,
-		"-osrt",
-		"-l", p.cfg.Whisper.Language,
-		"-t", strconv.Itoa(p.cfg.Whisper.Threads),
-		"-ml", "0", // No max length limit
-		"-mc", "0", // No max context limit
-		"-bo", "5", // Best of 5 for better accuracy
-		"--prompt", p.cfg.Whisper.Prompt,
-		"--output-file", outputPrefix,
+	// HTTP backends do not go through the executor, so bound them here
+	info, _ := ctx.Value(stageKey{}).(stageInfo)
+	if p.cfg.Whisper.Backend != transcriber.BackendCLI {
+		if timeout := p.commandTimeout(jobstore.StageTranscribe, info.duration); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
 	}
-
-	// Add GPU flag if enabled (Metal acceleration on Apple Silicon)
-	if p.cfg.Whisper.UseGPU {
-		// Metal is enabled by default in whisper.cpp on macOS
-		p.logger.Debug(ctx, "Metal GPU acceleration enabled")
+	if p.shouldChunk(info.duration) {
+		return p.transcribeChunked(ctx, audioPath, settings, info.duration)
 	}
+	srtPath := req.OutputPath
 
-	if _, err := p.executor.Execute(ctx, p.cfg.Whisper.BinaryPath, args...); err != nil {
-		return "", fmt.Errorf("whisper transcribe: %w", err)
+	if err := p.transcriber.Transcribe(ctx, req); err != nil {
+		return "", fmt.Errorf("%s backend: %w", p.cfg.Whisper.Backend, err)
 	}
 
-	srtPath := outputPrefix + ".srt"
 	p.logger.Info(ctx, "Transcription completed: %s", srtPath)
 	return srtPath, nil
 }
+
+// transcribeRequest builds the backend request; the SRT is written next to the audio
+func transcribeRequest(audioPath string, settings jobSettings) transcriber.Request {
+	return transcriber.Request{
+		AudioPath:  audioPath,
+		OutputPath: strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".srt",
+		Language:   settings.language,
+		Prompt:     settings.prompt,
+	}
+}
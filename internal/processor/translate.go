@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// translateSubtitles translates the transcript to translation.language and writes it to the
+// output folder as <baseName>.<lang>.srt. When the bilingual track is burned, a two-line
+// SRT (original above translation) is also written next to the temp transcript.
+// Returns the written paths keyed "translation" and "bilingual".
+func (p *implProcessor) translateSubtitles(ctx context.Context, srtPath, baseName, outDir string) (map[string]string, error) {
+	cues, err := subtitle.ReadSRTFile(srtPath)
+	if err != nil {
+		return nil, fmt.Errorf("read SRT: %w", err)
+	}
+
+	lang := p.cfg.Translation.Language
+	p.logger.Info(ctx, "Translating %d cues to %s", len(cues), lang)
+
+	translated, err := p.translator.Translate(ctx, cues, lang)
+	if err != nil {
+		return nil, err
+	}
+	p.rewrap(translated)
+
+	destPath := p.translationPath(baseName, outDir)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+
+	if err := subtitle.WriteSRTFile(destPath, translated); err != nil {
+		return nil, fmt.Errorf("write translation: %w", err)
+	}
+	p.logger.Info(ctx, "Translation saved: %s", destPath)
+	written := map[string]string{"translation": destPath}
+
+	if p.cfg.Translation.Burn == "bilingual" {
+		bilingualPath := bilingualPathFor(srtPath)
+		if err := subtitle.WriteSRTFile(bilingualPath, bilingual(cues, translated)); err != nil {
+			return nil, fmt.Errorf("write bilingual track: %w", err)
+		}
+		written["bilingual"] = bilingualPath
+	}
+	return written, nil
+}
+
+// translationPath is the output path of the translated SRT: <outDir>/<baseName>.<lang>.srt
+func (p *implProcessor) translationPath(baseName, outDir string) string {
+	return filepath.Join(p.cfg.Paths.Output, outDir, baseName+"."+p.cfg.Translation.Language+".srt")
+}
+
+// bilingualPathFor is the temp path of the two-line track burned instead of srtPath
+func bilingualPathFor(srtPath string) string {
+	return strings.TrimSuffix(srtPath, ".srt") + ".bilingual.srt"
+}
+
+// bilingual pairs each original cue with its translation: original lines first, translation below
+func bilingual(original, translated []subtitle.Cue) []subtitle.Cue {
+	out := make([]subtitle.Cue, len(original))
+	for i, c := range original {
+		lines := append(c.Lines(), translated[i].Lines()...)
+		out[i] = subtitle.Cue{Index: c.Index, Start: c.Start, End: c.End, Text: strings.Join(lines, "\n")}
+	}
+	return out
+}
@@ -0,0 +1,24 @@
+package progress
+
+import "time"
+
+// Progress is the latest known completion of the stage a job is running
+type Progress struct {
+	Stage     string
+	Percent   float64
+	ETA       time.Duration // zero until a first percentage is known
+	StartedAt time.Time     // when the stage started reporting
+	UpdatedAt time.Time
+}
+
+// Tracker keeps live progress per job for logs, the job API and other consumers.
+// It is safe for concurrent use.
+type Tracker interface {
+	// Update records percent (0-100) for stage of job and returns the previous and new state.
+	// A new stage restarts the ETA clock.
+	Update(jobID, stage string, percent float64) (prev, cur Progress)
+	// Get returns the progress of a running job
+	Get(jobID string) (Progress, bool)
+	// Clear forgets a job once it has finished
+	Clear(jobID string)
+}
@@ -0,0 +1,19 @@
+package progress
+
+import (
+	"sync"
+	"time"
+)
+
+type implTracker struct {
+	mu   sync.RWMutex
+	jobs map[string]Progress
+	now  func() time.Time
+}
+
+func New() Tracker {
+	return &implTracker{
+		jobs: make(map[string]Progress),
+		now:  time.Now,
+	}
+}
@@ -0,0 +1,42 @@
+package progress
+
+import "time"
+
+func (t *implTracker) Update(jobID, stage string, percent float64) (prev, cur Progress) {
+	percent = min(max(percent, 0), 100)
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev = t.jobs[jobID]
+	cur = prev
+	if prev.Stage != stage {
+		cur = Progress{Stage: stage, StartedAt: now}
+	}
+	cur.Percent = percent
+	cur.UpdatedAt = now
+
+	// Linear extrapolation from the time spent so far
+	cur.ETA = 0
+	if percent > 0 {
+		elapsed := now.Sub(cur.StartedAt)
+		cur.ETA = time.Duration(float64(elapsed) * (100 - percent) / percent)
+	}
+
+	t.jobs[jobID] = cur
+	return prev, cur
+}
+
+func (t *implTracker) Get(jobID string) (Progress, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	p, ok := t.jobs[jobID]
+	return p, ok
+}
+
+func (t *implTracker) Clear(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.jobs, jobID)
+}
@@ -0,0 +1,38 @@
+package progress
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := New().(*implTracker)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	_, cur := tracker.Update("job1", "burn", 0)
+	if cur.ETA != 0 {
+		t.Errorf("ETA = %v before any progress, want 0", cur.ETA)
+	}
+
+	now = now.Add(time.Minute)
+	_, cur = tracker.Update("job1", "burn", 25)
+	if cur.ETA != 3*time.Minute {
+		t.Errorf("ETA = %v at 25%% after 1m, want 3m", cur.ETA)
+	}
+
+	// A new stage restarts the clock
+	now = now.Add(time.Minute)
+	prev, cur := tracker.Update("job1", "mux", 50)
+	if prev.Stage != "burn" || cur.Stage != "mux" || cur.ETA != 0 {
+		t.Errorf("stage change: prev %+v, cur %+v", prev, cur)
+	}
+
+	if got, ok := tracker.Get("job1"); !ok || got.Percent != 50 {
+		t.Errorf("Get() = %+v, %v", got, ok)
+	}
+	tracker.Clear("job1")
+	if _, ok := tracker.Get("job1"); ok {
+		t.Error("Get() after Clear() should report nothing")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gomutex/godocx"
 	"github.com/gomutex/godocx/docx"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
 )
 
 const (
@@ -13,63 +14,47 @@ const (
 	fontSize = 13
 )
 
-var (
-	reHeading  = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
-	reBold     = regexp.MustCompile(`\*\*(.+?)\*\*`)
-	reBullet   = regexp.MustCompile(`^[\-\*]\s+(.+)$`)
-	reNumberd  = regexp.MustCompile(`^\d+\.\s+(.+)$`)
-	reSrtTime  = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}`)
-	reSrtIndex = regexp.MustCompile(`^\d+$`)
-)
+// reBold matches **bold** markers the LLM leaves in summary text
+var reBold = regexp.MustCompile(`\*\*(.+?)\*\*`)
 
-// markdownToDocx converts markdown text to a styled docx file.
-func markdownToDocx(title, markdown, outputPath string) error {
+// summaryToDocx writes sum as a styled docx file
+func summaryToDocx(sum *Summary, outputPath string, h Headings) error {
 	doc, err := godocx.NewDocument()
 	if err != nil {
 		return err
 	}
 
-	addStyledRun(doc.AddParagraph(""), title, true, 16)
-
-	lines := strings.Split(markdown, "\n")
-	for i := 0; i < len(lines); i++ {
-		line := lines[i]
-		trimmed := strings.TrimSpace(line)
-
-		if trimmed == "" || trimmed == "---" {
-			continue
-		}
-
-		if m := reHeading.FindStringSubmatch(trimmed); m != nil {
-			level := len(m[1])
-			size := headingSize(level)
-			p := doc.AddParagraph("")
-			addStyledRun(p, m[2], true, size)
-			continue
-		}
+	addStyledRun(doc.AddParagraph(""), sum.Title, true, headingSize(1))
+	if sum.Overview != "" {
+		addRichText(doc.AddParagraph(""), sum.Overview)
+	}
 
-		if m := reBullet.FindStringSubmatch(trimmed); m != nil {
-			p := doc.AddParagraph("")
-			addRichText(p, "• "+m[1])
-			continue
+	bullets := func(heading string, items []string) {
+		addStyledRun(doc.AddParagraph(""), heading, true, headingSize(2))
+		for _, item := range items {
+			addRichText(doc.AddParagraph(""), "• "+item)
 		}
-
-		if m := reNumberd.FindStringSubmatch(trimmed); m != nil {
-			p := doc.AddParagraph("")
-			addRichText(p, trimmed)
-			continue
+	}
+	for _, sec := range sum.Sections {
+		bullets(sec.Title, sec.Details)
+	}
+	if len(sum.KeyTerms) > 0 {
+		terms := make([]string, len(sum.KeyTerms))
+		for i, t := range sum.KeyTerms {
+			terms[i] = "**" + t.Term + "**: " + t.Explanation
 		}
-
-		p := doc.AddParagraph("")
-		addRichText(p, trimmed)
+		bullets(h.KeyTerms, terms)
+	}
+	if len(sum.ImportantNotes) > 0 {
+		bullets(h.Notes, sum.ImportantNotes)
 	}
 
 	return doc.SaveTo(outputPath)
 }
 
-// srtToDocx converts raw SRT subtitle content to a clean transcript docx.
-// Strips sequence numbers and timestamps, keeps only dialogue text.
-func srtToDocx(title, srtContent, outputPath string) error {
+// srtToDocx converts parsed subtitle cues to a clean transcript docx.
+// Sequence numbers and timestamps are dropped, only dialogue text is kept.
+func srtToDocx(title string, cues []subtitle.Cue, outputPath string) error {
 	doc, err := godocx.NewDocument()
 	if err != nil {
 		return err
@@ -78,15 +63,9 @@ func srtToDocx(title, srtContent, outputPath string) error {
 	addStyledRun(doc.AddParagraph(""), title, true, 16)
 	doc.AddParagraph("")
 
-	lines := strings.Split(srtContent, "\n")
 	var textLines []string
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || reSrtIndex.MatchString(trimmed) || reSrtTime.MatchString(trimmed) {
-			continue
-		}
-		textLines = append(textLines, trimmed)
+	for _, c := range cues {
+		textLines = append(textLines, c.Lines()...)
 	}
 
 	// Group into paragraphs: merge consecutive text lines, split on duplicates
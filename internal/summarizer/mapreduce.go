@@ -0,0 +1,176 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// maxAttempts is how often the summary is requested again when the reply is not a valid Summary
+const maxAttempts = 3
+
+// charsPerToken is a conservative estimate for Vietnamese and English text; it errs towards
+// more tokens so a part never reaches the real limit
+const charsPerToken = 3
+
+// transcriptPart is a time-ordered run of cues summarized on its own
+type transcriptPart struct {
+	start, end time.Duration
+	text       string
+}
+
+// summarize returns the structured summary of cues, prompted with the style template. The
+// transcript is sent without SRT indices and timestamps; when it is estimated above
+// maxTokens it is summarized in time-ordered parts whose notes are then reduced into one
+// summary by the style template.
+func (s *implSummarizer) summarize(ctx context.Context, cues []subtitle.Cue, data PromptData) (*Summary, error) {
+	data.Transcript = subtitle.PlainText(cues)
+	tokens := estimateTokens(data.Transcript)
+	if tokens <= s.maxTokens {
+		return s.generateSummary(ctx, data)
+	}
+
+	parts := splitTranscript(cues, s.maxTokens)
+	s.logger.Info(ctx, "  Transcript is ~%d tokens, summarizing it in %d parts", tokens, len(parts))
+
+	notes := make([]string, len(parts))
+	for i, part := range parts {
+		partData := data
+		partData.Transcript = part.text
+		partData.Part, partData.Parts = i+1, len(parts)
+		partData.Start, partData.End = subtitle.FormatTimestamp(part.start), subtitle.FormatTimestamp(part.end)
+		note, err := s.generate(ctx, s.prompts.part, partData)
+		if err != nil {
+			return nil, fmt.Errorf("summarize part %d/%d: %w", i+1, len(parts), err)
+		}
+		notes[i] = strings.TrimSpace(note)
+	}
+
+	// Merge neighbouring notes until all of them fit in the final prompt
+	for len(notes) > 1 && estimateTokens(joinNotes(notes)) > s.maxTokens {
+		merged, err := s.mergeNotes(ctx, notes, data)
+		if err != nil {
+			return nil, err
+		}
+		notes = merged
+	}
+
+	data.Transcript = joinNotes(notes)
+	data.Parts = len(parts)
+	return s.generateSummary(ctx, data)
+}
+
+// generateSummary asks for a Summary with the style template and validates the reply,
+// asking again when it does not fit the schema
+func (s *implSummarizer) generateSummary(ctx context.Context, data PromptData) (*Summary, error) {
+	prompt, err := render(s.prompts.summary, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		reply, err := s.client.GenerateJSON(ctx, prompt, summarySchema)
+		if err != nil {
+			return nil, err
+		}
+		sum, err := parseSummary(reply)
+		if err == nil {
+			return sum, nil
+		}
+		lastErr = err
+		s.logger.Warn(ctx, "  Summary reply rejected (attempt %d/%d): %v", attempt, maxAttempts, err)
+	}
+	return nil, lastErr
+}
+
+// mergeNotes combines consecutive notes into groups of at most maxTokens each, always
+// pairing at least two so every round shrinks the list
+func (s *implSummarizer) mergeNotes(ctx context.Context, notes []string, data PromptData) ([]string, error) {
+	var merged []string
+	for i := 0; i < len(notes); {
+		j := i + 1
+		for j < len(notes) && (j-i < 2 || estimateTokens(joinNotes(notes[i:j+1])) <= s.maxTokens) {
+			j++
+		}
+		if j-i == 1 {
+			merged = append(merged, notes[i])
+			i = j
+			continue
+		}
+		data.Transcript = joinNotes(notes[i:j])
+		note, err := s.generate(ctx, s.prompts.merge, data)
+		if err != nil {
+			return nil, fmt.Errorf("merge part notes: %w", err)
+		}
+		merged = append(merged, strings.TrimSpace(note))
+		i = j
+	}
+	return merged, nil
+}
+
+// generate renders tmpl with data and sends the prompt
+func (s *implSummarizer) generate(ctx context.Context, tmpl *template.Template, data PromptData) (string, error) {
+	prompt, err := render(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+	return s.client.Generate(ctx, prompt)
+}
+
+// splitTranscript groups cues in order into parts of at most maxTokens each; a single
+// cue larger than that gets a part of its own
+func splitTranscript(cues []subtitle.Cue, maxTokens int) []transcriptPart {
+	var parts []transcriptPart
+	var cur transcriptPart
+	var sb strings.Builder
+	chars := 0
+	flush := func() {
+		if sb.Len() == 0 {
+			return
+		}
+		cur.text = sb.String()
+		parts = append(parts, cur)
+		sb.Reset()
+		chars = 0
+	}
+
+	for _, c := range cues {
+		line := strings.Join(c.Lines(), " ")
+		if line == "" {
+			continue
+		}
+		n := utf8.RuneCountInString(line) + 1
+		if sb.Len() > 0 && (chars+n+charsPerToken-1)/charsPerToken > maxTokens {
+			flush()
+		}
+		if sb.Len() == 0 {
+			cur = transcriptPart{start: c.Start}
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+		chars += n
+		cur.end = c.End
+	}
+	flush()
+	return parts
+}
+
+// joinNotes numbers the notes of consecutive parts
+func joinNotes(notes []string) string {
+	blocks := make([]string, len(notes))
+	for i, note := range notes {
+		blocks[i] = fmt.Sprintf("### Part %d\n\n%s", i+1, note)
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// estimateTokens approximates the number of model tokens in text
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
@@ -0,0 +1,169 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// fakeClient records prompts and answers each with a short note naming the call; JSON
+// requests get a summary titled that way, after invalid malformed replies
+type fakeClient struct {
+	mu      sync.Mutex
+	prompts []string
+	invalid int
+}
+
+func (f *fakeClient) Generate(_ context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prompts = append(f.prompts, prompt)
+	return fmt.Sprintf("note %d", len(f.prompts)), nil
+}
+
+func (f *fakeClient) GenerateJSON(ctx context.Context, prompt string, _ map[string]any) (string, error) {
+	note, _ := f.Generate(ctx, prompt)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.invalid > 0 {
+		f.invalid--
+		return `{"title": "", "sections": []}`, nil
+	}
+	return fmt.Sprintf(`{"title": %q, "overview": "", "sections": [{"title": "Step", "details": ["do it"]}], "key_terms": [], "important_notes": []}`, note), nil
+}
+
+// newTestSummarizer loads the shipped prompts with the detailed style
+func newTestSummarizer(t *testing.T, client *fakeClient, maxTokens int) *implSummarizer {
+	t.Helper()
+	prompts, err := LoadPrompts("../../prompts", "detailed")
+	if err != nil {
+		t.Fatalf("LoadPrompts() error = %v", err)
+	}
+	opts := Options{Prompts: prompts, Language: "tiếng Việt", MaxTokens: maxTokens}
+	return New(client, opts, metrics.NewNop(), logger.New("error")).(*implSummarizer)
+}
+
+func testCues(n int, text string) []subtitle.Cue {
+	cues := make([]subtitle.Cue, n)
+	for i := range cues {
+		cues[i] = subtitle.Cue{
+			Index: i + 1,
+			Start: time.Duration(i) * time.Minute,
+			End:   time.Duration(i)*time.Minute + 30*time.Second,
+			Text:  fmt.Sprintf("%s %d", text, i+1),
+		}
+	}
+	return cues
+}
+
+func TestSummarizeSinglePass(t *testing.T) {
+	client := &fakeClient{}
+	s := newTestSummarizer(t, client, 1000)
+
+	if _, err := s.summarize(context.Background(), testCues(3, "short line"), PromptData{VideoName: "intro"}); err != nil {
+		t.Fatalf("summarize() error = %v", err)
+	}
+	if len(client.prompts) != 1 {
+		t.Fatalf("summarize() made %d calls, want 1", len(client.prompts))
+	}
+	prompt := client.prompts[0]
+	if strings.Contains(prompt, "-->") || strings.Contains(prompt, "00:00:00") {
+		t.Errorf("prompt still contains SRT timing:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "short line 1\nshort line 2\nshort line 3\n") {
+		t.Errorf("prompt is missing the plain transcript:\n%s", prompt)
+	}
+}
+
+func TestSummarizeMapReduce(t *testing.T) {
+	client := &fakeClient{}
+	// Each cue is ~7 tokens, so parts hold three cues
+	s := newTestSummarizer(t, client, 20)
+
+	summary, err := s.summarize(context.Background(), testCues(7, "a line of speech"), PromptData{VideoName: "intro"})
+	if err != nil {
+		t.Fatalf("summarize() error = %v", err)
+	}
+
+	if len(client.prompts) != 4 {
+		t.Fatalf("summarize() made %d calls, want 3 parts + 1 reduce", len(client.prompts))
+	}
+	for i, want := range []string{"PHẦN 1/3", "PHẦN 2/3", "PHẦN 3/3"} {
+		if !strings.Contains(client.prompts[i], want) {
+			t.Errorf("prompt %d does not contain %q", i, want)
+		}
+	}
+	if !strings.Contains(client.prompts[1], "từ 00:03:00,000 đến 00:05:30,000") {
+		t.Errorf("part 2 prompt has the wrong time range:\n%s", client.prompts[1])
+	}
+	reduce := client.prompts[3]
+	if !strings.Contains(reduce, `"important_notes"`) || !strings.Contains(reduce, "tiêu đề tổng quan") {
+		t.Errorf("reduce prompt lost the summary sections:\n%s", reduce)
+	}
+	if strings.Index(reduce, "note 1") > strings.Index(reduce, "note 2") || strings.Index(reduce, "note 2") > strings.Index(reduce, "note 3") {
+		t.Errorf("reduce prompt has the notes out of order:\n%s", reduce)
+	}
+	if !strings.Contains(reduce, "### Part 3\n\nnote 3") {
+		t.Errorf("reduce prompt lacks the language-neutral part headings:\n%s", reduce)
+	}
+	if summary.Title != "note 4" {
+		t.Errorf("summarize() title = %q, want the reduce reply", summary.Title)
+	}
+}
+
+func TestSummarizeRetriesInvalidReply(t *testing.T) {
+	client := &fakeClient{invalid: 1}
+	s := newTestSummarizer(t, client, 1000)
+
+	summary, err := s.summarize(context.Background(), testCues(3, "short line"), PromptData{VideoName: "intro"})
+	if err != nil {
+		t.Fatalf("summarize() error = %v", err)
+	}
+	if len(client.prompts) != 2 || summary.Title != "note 2" {
+		t.Errorf("summarize() made %d calls and returned %q, want a retry after the invalid reply", len(client.prompts), summary.Title)
+	}
+
+	client = &fakeClient{invalid: maxAttempts}
+	s = newTestSummarizer(t, client, 1000)
+	if _, err := s.summarize(context.Background(), testCues(3, "short line"), PromptData{}); err == nil {
+		t.Error("summarize() error = nil, want the validation error once attempts run out")
+	}
+}
+
+func TestSplitTranscript(t *testing.T) {
+	cues := testCues(5, "a line of speech")
+	cues[2].Text = ""
+
+	parts := splitTranscript(cues, 14)
+	if len(parts) != 2 {
+		t.Fatalf("splitTranscript() = %d parts, want 2", len(parts))
+	}
+	if parts[0].text != "a line of speech 1\na line of speech 2\n" {
+		t.Errorf("part 1 text = %q", parts[0].text)
+	}
+	if parts[1].start != 3*time.Minute || parts[1].end != 4*time.Minute+30*time.Second {
+		t.Errorf("part 2 spans %v-%v, want 3m0s-4m30s", parts[1].start, parts[1].end)
+	}
+}
+
+func TestMergeNotes(t *testing.T) {
+	client := &fakeClient{}
+	s := newTestSummarizer(t, client, 20)
+
+	notes := []string{strings.Repeat("x", 30), strings.Repeat("y", 30), strings.Repeat("z", 30)}
+	merged, err := s.mergeNotes(context.Background(), notes, PromptData{})
+	if err != nil {
+		t.Fatalf("mergeNotes() error = %v", err)
+	}
+	// Notes too large to group are still paired so the list always shrinks
+	if len(merged) != 2 || merged[0] != "note 1" || merged[1] != notes[2] {
+		t.Errorf("mergeNotes() = %q", merged)
+	}
+}
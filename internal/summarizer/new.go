@@ -1,23 +1,46 @@
 package summarizer
 
 import (
+	"github.com/nguyentantai21042004/caption-flow/internal/llm"
 	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
 )
 
+// DefaultMaxTokens is the estimated transcript size summarized in one request when none is configured
+const DefaultMaxTokens = 100000
+
+// Options configures a Summarizer
+type Options struct {
+	Prompts   *Prompts // from LoadPrompts
+	Language  string   // output language passed to the prompts
+	Headings  Headings // section titles added by the renderers, in Language
+	Glossary  []string // canonical terms passed to the prompts
+	MaxTokens int      // estimated transcript size summarized in one request
+}
+
 type implSummarizer struct {
-	apiKeys    []string
-	currentKey int
-	logger     logger.Logger
-	model      string
+	client    llm.Client
+	prompts   *Prompts
+	language  string
+	headings  Headings
+	glossary  []string
+	maxTokens int
+	metrics   metrics.Metrics
+	logger    logger.Logger
 }
 
-func New(apiKeys []string, model string, log logger.Logger) Summarizer {
-	if model == "" {
-		model = "gemini-2.5-flash"
+func New(client llm.Client, opts Options, m metrics.Metrics, log logger.Logger) Summarizer {
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultMaxTokens
 	}
 	return &implSummarizer{
-		apiKeys: apiKeys,
-		logger:  log,
-		model:   model,
+		client:    client,
+		prompts:   opts.Prompts,
+		language:  opts.Language,
+		headings:  opts.Headings,
+		glossary:  opts.Glossary,
+		maxTokens: opts.MaxTokens,
+		metrics:   m,
+		logger:    log,
 	}
 }
@@ -0,0 +1,111 @@
+package summarizer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Prompt template files below the prompts directory
+const (
+	stylesDir = "styles"     // one <style>.tmpl per summary style
+	partFile  = "part.tmpl"  // notes on one part of a long transcript
+	mergeFile = "merge.tmpl" // merges the notes of consecutive parts
+)
+
+// funcs are the functions available in prompt templates besides the text/template builtins
+var funcs = template.FuncMap{
+	"join": strings.Join, // {{join .Glossary ", "}}
+}
+
+// PromptData is the data prompt templates are rendered with
+type PromptData struct {
+	VideoName string
+	Duration  string   // e.g. "1h23m45s", taken from the last cue
+	Language  string   // output language, gemini.language
+	Folder    string   // subfolder of the output dir (course), empty at the top level
+	Glossary  []string // canonical terms from whisper.glossary
+	// Transcript is the dialogue text. In a style template rendered after a long transcript
+	// was split, it holds the notes of all parts instead and Parts is their number.
+	Transcript string
+	// Part (from 1), Parts, Start and End describe the part a part.tmpl is rendered for
+	Part, Parts int
+	Start, End  string
+}
+
+// Prompts are the parsed templates of one summary style
+type Prompts struct {
+	Style   string
+	summary *template.Template
+	part    *template.Template
+	merge   *template.Template
+}
+
+// LoadPrompts parses styles/<style>.tmpl, part.tmpl and merge.tmpl from dir. Every template
+// is rendered once with empty data so a misspelt variable fails here rather than mid-run.
+func LoadPrompts(dir, style string) (*Prompts, error) {
+	stylePath := filepath.Join(dir, stylesDir, style+".tmpl")
+	if _, err := os.Stat(stylePath); err != nil {
+		styles, _ := Styles(dir)
+		return nil, fmt.Errorf("prompt style %q not found in %s (available: %s)", style, filepath.Join(dir, stylesDir), strings.Join(styles, ", "))
+	}
+
+	p := &Prompts{Style: style}
+	for _, t := range []struct {
+		dst  **template.Template
+		path string
+	}{
+		{&p.summary, stylePath},
+		{&p.part, filepath.Join(dir, partFile)},
+		{&p.merge, filepath.Join(dir, mergeFile)},
+	} {
+		tmpl, err := parseTemplate(t.path)
+		if err != nil {
+			return nil, err
+		}
+		*t.dst = tmpl
+	}
+	return p, nil
+}
+
+// Styles lists the style names in dir/styles
+func Styles(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, stylesDir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+	styles := make([]string, len(paths))
+	for i, path := range paths {
+		styles[i] = strings.TrimSuffix(filepath.Base(path), ".tmpl")
+	}
+	sort.Strings(styles)
+	return styles, nil
+}
+
+func parseTemplate(path string) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read prompt: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", path, err)
+	}
+	if err := tmpl.Execute(io.Discard, PromptData{}); err != nil {
+		return nil, fmt.Errorf("check prompt %s: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// render executes tmpl with data
+func render(tmpl *template.Template, data PromptData) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
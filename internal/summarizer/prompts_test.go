@@ -0,0 +1,92 @@
+package summarizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePrompts(t *testing.T, style string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		filepath.Join(stylesDir, "short.tmpl"): style,
+		partFile:                               "Part {{.Part}}/{{.Parts}}: {{.Transcript}}",
+		mergeFile:                              "Merge: {{.Transcript}}",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadPrompts(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   string
+		load    string
+		wantErr string
+	}{
+		{"valid", "{{.VideoName}} ({{.Folder}}, {{.Duration}}) in {{.Language}}: {{join .Glossary \", \"}}", "short", ""},
+		{"unknown style", "{{.Transcript}}", "long", `prompt style "long" not found`},
+		{"syntax error", "{{if .Parts}}", "short", "parse prompt"},
+		{"misspelt variable", "{{.VideoTitle}}", "short", "check prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadPrompts(writePrompts(t, tt.style), tt.load)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadPrompts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPrompts() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderPrompt(t *testing.T) {
+	prompts, err := LoadPrompts(writePrompts(t, "{{.VideoName}} ({{.Folder}}, {{.Duration}}) in {{.Language}}: {{join .Glossary \", \"}}"), "short")
+	if err != nil {
+		t.Fatalf("LoadPrompts() error = %v", err)
+	}
+	got, err := render(prompts.summary, PromptData{
+		VideoName: "intro",
+		Folder:    "course-a",
+		Duration:  "1h2m3s",
+		Language:  "English",
+		Glossary:  []string{"Kubernetes", "kubectl"},
+	})
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	if want := "intro (course-a, 1h2m3s) in English: Kubernetes, kubectl"; got != want {
+		t.Errorf("render() = %q, want %q", got, want)
+	}
+}
+
+// The shipped styles are edited by hand, so each one must load
+func TestShippedPrompts(t *testing.T) {
+	styles, err := Styles("../../prompts")
+	if err != nil {
+		t.Fatalf("Styles() error = %v", err)
+	}
+	if len(styles) < 3 {
+		t.Fatalf("Styles() = %v, want at least detailed, brief and checklist", styles)
+	}
+	for _, style := range styles {
+		if _, err := LoadPrompts("../../prompts", style); err != nil {
+			t.Errorf("LoadPrompts(%q) error = %v", style, err)
+		}
+	}
+}
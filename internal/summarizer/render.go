@@ -0,0 +1,142 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+)
+
+// Headings are the section titles added around the LLM's content, in the output language
+type Headings struct {
+	KeyTerms string
+	Notes    string
+}
+
+// summaryExts are the files written for every summary, next to each other
+var summaryExts = []string{".docx", ".md", ".html", ".json"}
+
+// writeSummary renders sum to <basePath>.docx, .md, .html and .json and returns the paths written
+func writeSummary(sum *Summary, basePath string, h Headings) ([]string, error) {
+	paths := make([]string, 0, len(summaryExts))
+	for _, ext := range summaryExts {
+		path := basePath + ext
+		var err error
+		switch ext {
+		case ".docx":
+			err = summaryToDocx(sum, path, h)
+		case ".md":
+			err = os.WriteFile(path, []byte(summaryMarkdown(sum, h)), 0644)
+		case ".html":
+			err = writeSummaryHTML(sum, path, h)
+		case ".json":
+			err = writeSummaryJSON(sum, path)
+		}
+		if err != nil {
+			return paths, fmt.Errorf("write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// summaryMarkdown renders sum as a markdown document
+func summaryMarkdown(sum *Summary, h Headings) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", sum.Title)
+	if sum.Overview != "" {
+		fmt.Fprintf(&sb, "\n%s\n", sum.Overview)
+	}
+	for _, sec := range sum.Sections {
+		fmt.Fprintf(&sb, "\n## %s\n\n", sec.Title)
+		for _, d := range sec.Details {
+			fmt.Fprintf(&sb, "- %s\n", d)
+		}
+	}
+	if len(sum.KeyTerms) > 0 {
+		fmt.Fprintf(&sb, "\n## %s\n\n", h.KeyTerms)
+		for _, t := range sum.KeyTerms {
+			fmt.Fprintf(&sb, "- **%s**: %s\n", t.Term, t.Explanation)
+		}
+	}
+	if len(sum.ImportantNotes) > 0 {
+		fmt.Fprintf(&sb, "\n## %s\n\n", h.Notes)
+		for _, n := range sum.ImportantNotes {
+			fmt.Fprintf(&sb, "- %s\n", n)
+		}
+	}
+	return sb.String()
+}
+
+var summaryHTML = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"inline": inlineHTML,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Summary.Title}}</title>
+</head>
+<body>
+<h1>{{.Summary.Title}}</h1>
+{{- if .Summary.Overview}}
+<p>{{inline .Summary.Overview}}</p>
+{{- end}}
+{{- range .Summary.Sections}}
+<h2>{{.Title}}</h2>
+<ul>
+{{- range .Details}}
+<li>{{inline .}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Summary.KeyTerms}}
+<h2>{{.TermsHeading}}</h2>
+<dl>
+{{- range .Summary.KeyTerms}}
+<dt>{{.Term}}</dt>
+<dd>{{inline .Explanation}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+{{- if .Summary.ImportantNotes}}
+<h2>{{.NotesHeading}}</h2>
+<ul>
+{{- range .Summary.ImportantNotes}}
+<li>{{inline .}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeSummaryHTML(sum *Summary, path string, h Headings) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Summary                    *Summary
+		TermsHeading, NotesHeading string
+	}{sum, h.KeyTerms, h.Notes}
+	if err := summaryHTML.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// inlineHTML escapes text and turns **bold** markers into <strong>
+func inlineHTML(text string) template.HTML {
+	escaped := template.HTMLEscapeString(text)
+	return template.HTML(reBold.ReplaceAllString(escaped, "<strong>$1</strong>"))
+}
+
+func writeSummaryJSON(sum *Summary, path string) error {
+	data, err := json.MarshalIndent(sum, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -3,34 +3,21 @@ package summarizer
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
-	"google.golang.org/genai"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
 )
 
-const summaryPrompt = `Bạn là một chuyên gia phân tích nội dung video đào tạo. Dựa trên phụ đề bên dưới, hãy viết một bản tóm tắt CHI TIẾT bằng TIẾNG VIỆT.
-
-Yêu cầu:
-- Bắt đầu bằng tiêu đề tổng quan (1 câu) mô tả chủ đề video
-- Liệt kê TẤT CẢ các bước / nội dung chính theo thứ tự xuất hiện
-- Giải thích chi tiết từng bước, bao gồm các lưu ý, mẹo, cảnh báo quan trọng
-- Nếu có thuật ngữ chuyên ngành, giữ nguyên thuật ngữ tiếng Anh trong ngoặc
-- Sử dụng format markdown: heading, bullet points, bold cho từ khóa quan trọng
-- Cuối cùng thêm phần "Lưu ý quan trọng" nếu có thông tin cần nhấn mạnh
-
-Phụ đề video:
----
-%s
----`
-
-// SummarizeAll discovers SRT files in outputDir (root), then for each:
-//   - writes transcript docx to outputDir/transcripts/
-//   - calls Gemini and writes summary docx to outputDir/summaries/
-//   - moves the processed SRT to outputDir/archived/
+// SummarizeAll discovers SRT files in outputDir (including course subfolders), then for each:
+//   - writes transcript docx to outputDir/transcripts/<subfolder>/
+//   - asks the LLM for a structured summary with the configured prompt style and writes it
+//     as docx, md, html and json to outputDir/summaries/<subfolder>/
+//   - moves the processed SRT to outputDir/archived/<subfolder>/
 func (s *implSummarizer) SummarizeAll(ctx context.Context, outputDir string) error {
 	srtFiles, err := s.discoverSRTFiles(outputDir)
 	if err != nil {
@@ -46,10 +33,8 @@ func (s *implSummarizer) SummarizeAll(ctx context.Context, outputDir string) err
 	summariesDir := filepath.Join(outputDir, "summaries")
 	archivedDir := filepath.Join(outputDir, "archived")
 
-	for _, dir := range []string{transcriptsDir, summariesDir, archivedDir} {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("create dir %s: %w", dir, err)
-		}
+	if err := mkdirAll(transcriptsDir, summariesDir, archivedDir); err != nil {
+		return err
 	}
 
 	s.logger.Info(ctx, "Found %d SRT files to process", len(srtFiles))
@@ -64,6 +49,20 @@ func (s *implSummarizer) SummarizeAll(ctx context.Context, outputDir string) err
 		videoName := strings.TrimSuffix(filepath.Base(srtPath), ".srt")
 		s.logger.Info(ctx, "[%d/%d] Processing: %s", i+1, len(srtFiles), videoName)
 
+		// Mirror the SRT's subfolder below transcripts/, summaries/ and archived/
+		relDir, err := filepath.Rel(outputDir, filepath.Dir(srtPath))
+		if err != nil {
+			relDir = "."
+		}
+		txDir := filepath.Join(transcriptsDir, relDir)
+		sumDir := filepath.Join(summariesDir, relDir)
+		arcDir := filepath.Join(archivedDir, relDir)
+		if err := mkdirAll(txDir, sumDir, arcDir); err != nil {
+			s.logger.Error(ctx, "Failed to create output folders for %s: %v", videoName, err)
+			failCount++
+			continue
+		}
+
 		content, err := os.ReadFile(srtPath)
 		if err != nil {
 			s.logger.Error(ctx, "Failed to read %s: %v", srtPath, err)
@@ -72,35 +71,45 @@ func (s *implSummarizer) SummarizeAll(ctx context.Context, outputDir string) err
 		}
 		srtText := string(content)
 
-		// 1) Transcript DOCX — raw SRT content formatted as docx
-		txDocx := filepath.Join(transcriptsDir, videoName+".docx")
-		if err := srtToDocx(videoName, srtText, txDocx); err != nil {
+		cues, err := subtitle.ParseSRT(strings.NewReader(srtText))
+		if err != nil {
+			s.logger.Error(ctx, "Failed to parse %s: %v", srtPath, err)
+			failCount++
+			continue
+		}
+
+		// 1) Transcript DOCX — dialogue text of the SRT formatted as docx
+		txDocx := filepath.Join(txDir, videoName+".docx")
+		if err := srtToDocx(videoName, cues, txDocx); err != nil {
 			s.logger.Error(ctx, "Failed to write transcript %s: %v", txDocx, err)
 			failCount++
 			continue
 		}
 		s.logger.Info(ctx, "  ✓ Transcript: %s", txDocx)
 
-		// 2) Summary DOCX — LLM-generated summary
-		summary, err := s.callGemini(ctx, srtText)
+		// 2) Summary — LLM-generated structured summary rendered to DOCX, Markdown, HTML and JSON
+		started := time.Now()
+		summary, err := s.summarize(ctx, cues, s.promptData(videoName, relDir, cues))
+		s.metrics.ObserveStage("summarize", time.Since(started), 0, err)
 		if err != nil {
 			s.logger.Error(ctx, "Failed to summarize %s: %v", videoName, err)
 			failCount++
 			continue
 		}
 
-		sumDocx := filepath.Join(summariesDir, videoName+".docx")
-		if err := markdownToDocx(videoName, strings.TrimSpace(summary), sumDocx); err != nil {
-			s.logger.Error(ctx, "Failed to write summary %s: %v", sumDocx, err)
+		sumPaths, err := writeSummary(summary, filepath.Join(sumDir, videoName), s.headings)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to write summary: %v", err)
 			failCount++
 			continue
 		}
-		s.logger.Info(ctx, "  ✓ Summary:    %s", sumDocx)
+		s.logger.Info(ctx, "  ✓ Summary:    %s", strings.Join(sumPaths, ", "))
 
-		// 3) Archive — move processed SRT so it won't be re-processed
-		srtDest := filepath.Join(archivedDir, filepath.Base(srtPath))
-		if err := os.Rename(srtPath, srtDest); err != nil {
-			s.logger.Warn(ctx, "Failed to archive SRT %s: %v", srtPath, err)
+		// 3) Archive — move processed SRT and its translations so they won't be re-processed
+		for _, path := range append([]string{srtPath}, translationsOf(srtPath)...) {
+			if err := os.Rename(path, filepath.Join(arcDir, filepath.Base(path))); err != nil {
+				s.logger.Warn(ctx, "Failed to archive SRT %s: %v", path, err)
+			}
 		}
 
 		s.logger.Info(ctx, "[DONE] %s", videoName)
@@ -108,7 +117,7 @@ func (s *implSummarizer) SummarizeAll(ctx context.Context, outputDir string) err
 
 		// Rate limiting delay between successfully processed files (except the last one)
 		if i < len(srtFiles)-1 {
-			s.logger.Info(ctx, "Sleeping 3s to respect Gemini API rate limits...")
+			s.logger.Info(ctx, "Sleeping 3s to respect API rate limits...")
 			time.Sleep(3 * time.Second)
 		}
 	}
@@ -117,82 +126,92 @@ func (s *implSummarizer) SummarizeAll(ctx context.Context, outputDir string) err
 	return nil
 }
 
-// callGemini sends the transcript to Gemini and returns the summary text.
-// Rotates API keys on 429 / quota errors.
-func (s *implSummarizer) callGemini(ctx context.Context, transcript string) (string, error) {
-	prompt := fmt.Sprintf(summaryPrompt, transcript)
-
-	attempts := len(s.apiKeys) * 3 // Try each key multiple times with backoff
-	var lastErr error
-	backoff := 5 * time.Second
+// promptData fills the template variables known before the transcript is split
+func (s *implSummarizer) promptData(videoName, relDir string, cues []subtitle.Cue) PromptData {
+	data := PromptData{
+		VideoName: videoName,
+		Language:  s.language,
+		Glossary:  s.glossary,
+	}
+	if relDir != "." {
+		data.Folder = filepath.ToSlash(relDir)
+	}
+	if len(cues) > 0 {
+		data.Duration = cues[len(cues)-1].End.Round(time.Second).String()
+	}
+	return data
+}
 
-	for i := 0; i < attempts; i++ {
-		key := s.apiKeys[s.currentKey]
+// discoverSRTFiles walks dir for SRT files, skipping the folders the pipeline writes its own results to
+func (s *implSummarizer) discoverSRTFiles(dir string) ([]string, error) {
+	skip := map[string]bool{
+		filepath.Join(dir, "transcripts"): true,
+		filepath.Join(dir, "summaries"):   true,
+		filepath.Join(dir, "archived"):    true,
+		filepath.Join(dir, "videos"):      true,
+	}
 
-		client, err := genai.NewClient(ctx, &genai.ClientConfig{
-			APIKey:  key,
-			Backend: genai.BackendGeminiAPI,
-		})
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
-			lastErr = fmt.Errorf("create client: %w", err)
-			s.rotateKey()
-			continue
+			return err
 		}
-
-		result, err := client.Models.GenerateContent(ctx, s.model, genai.Text(prompt), nil)
-		if err != nil {
-			errMsg := err.Error()
-			if strings.Contains(errMsg, "429") || strings.Contains(errMsg, "quota") || strings.Contains(errMsg, "RESOURCE_EXHAUSTED") || strings.Contains(errMsg, "retry in") {
-				s.logger.Warn(ctx, "Key %d rate limited, rotating... (attempt %d/%d). Sleeping for %v", s.currentKey+1, i+1, attempts, backoff)
-				s.rotateKey()
-				lastErr = err
-
-				time.Sleep(backoff)
-				backoff *= 2 // Exponential backoff
-				if backoff > 60*time.Second {
-					backoff = 60 * time.Second
-				}
-				continue
+		if path != dir && (skip[path] || strings.HasPrefix(d.Name(), ".")) {
+			if d.IsDir() {
+				return filepath.SkipDir
 			}
-			return "", fmt.Errorf("generate content: %w", err)
+			return nil
 		}
-
-		if result != nil && len(result.Candidates) > 0 && result.Candidates[0].Content != nil {
-			var text string
-			for _, part := range result.Candidates[0].Content.Parts {
-				if part.Text != "" {
-					text += part.Text
-				}
-			}
-			return text, nil
+		if !d.IsDir() && strings.ToLower(filepath.Ext(d.Name())) == ".srt" && !isTranslation(path) {
+			files = append(files, path)
 		}
-
-		return "", fmt.Errorf("empty response from Gemini")
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return "", fmt.Errorf("all API keys exhausted: %w", lastErr)
+	sort.Strings(files)
+	return files, nil
 }
 
-func (s *implSummarizer) rotateKey() {
-	s.currentKey = (s.currentKey + 1) % len(s.apiKeys)
+// isTranslation reports whether path is a translated <name>.<lang>.srt sitting next to <name>.srt
+func isTranslation(path string) bool {
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	lang := filepath.Ext(base)
+	if lang == "" {
+		return false
+	}
+	_, err := os.Stat(strings.TrimSuffix(base, lang) + ".srt")
+	return err == nil
 }
 
-func (s *implSummarizer) discoverSRTFiles(dir string) ([]string, error) {
-	entries, err := os.ReadDir(dir)
+// translationsOf lists the <name>.<lang>.srt files next to srtPath
+func translationsOf(srtPath string) []string {
+	entries, err := os.ReadDir(filepath.Dir(srtPath))
 	if err != nil {
-		return nil, err
+		return nil
 	}
-
-	var files []string
+	prefix := strings.TrimSuffix(filepath.Base(srtPath), ".srt") + "."
+	var paths []string
 	for _, e := range entries {
-		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+		name := e.Name()
+		if e.IsDir() || name == filepath.Base(srtPath) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".srt") {
 			continue
 		}
-		if strings.ToLower(filepath.Ext(e.Name())) == ".srt" {
-			files = append(files, filepath.Join(dir, e.Name()))
+		lang := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".srt")
+		if lang != "" && !strings.Contains(lang, ".") {
+			paths = append(paths, filepath.Join(filepath.Dir(srtPath), name))
 		}
 	}
+	return paths
+}
 
-	sort.Strings(files)
-	return files, nil
+func mkdirAll(dirs ...string) error {
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create dir %s: %w", dir, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,131 @@
+package summarizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summary is the structured summary the LLM returns for a video
+type Summary struct {
+	Title          string    `json:"title"`
+	Overview       string    `json:"overview"`
+	Sections       []Section `json:"sections"` // steps or topics in the order they appear
+	KeyTerms       []Term    `json:"key_terms"`
+	ImportantNotes []string  `json:"important_notes"`
+}
+
+// Section is one step or topic of the video
+type Section struct {
+	Title   string   `json:"title"`
+	Details []string `json:"details"`
+}
+
+// Term is a technical term with a short explanation
+type Term struct {
+	Term        string `json:"term"`
+	Explanation string `json:"explanation"`
+}
+
+// summarySchema is the JSON schema of Summary sent with the request. Every property is
+// required and no others are allowed, as strict structured output modes demand.
+var summarySchema = object(map[string]any{
+	"title":    str(),
+	"overview": str(),
+	"sections": array(object(map[string]any{
+		"title":   str(),
+		"details": array(str()),
+	})),
+	"key_terms": array(object(map[string]any{
+		"term":        str(),
+		"explanation": str(),
+	})),
+	"important_notes": array(str()),
+})
+
+func object(properties map[string]any) map[string]any {
+	required := make([]string, 0, len(properties))
+	for name := range properties {
+		required = append(required, name)
+	}
+	sort.Strings(required)
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func array(items map[string]any) map[string]any {
+	return map[string]any{"type": "array", "items": items}
+}
+
+func str() map[string]any {
+	return map[string]any{"type": "string"}
+}
+
+// parseSummary decodes the JSON object in reply into a Summary and validates it
+func parseSummary(reply string) (*Summary, error) {
+	first, last := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("reply contains no JSON object")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(reply[first : last+1])))
+	dec.DisallowUnknownFields()
+	var sum Summary
+	if err := dec.Decode(&sum); err != nil {
+		return nil, fmt.Errorf("decode summary: %w", err)
+	}
+	if err := sum.Validate(); err != nil {
+		return nil, err
+	}
+	return &sum, nil
+}
+
+// Validate checks the summary has a title and at least one section, that every section
+// and term is named, and trims stray whitespace
+func (s *Summary) Validate() error {
+	s.Title = strings.TrimSpace(s.Title)
+	s.Overview = strings.TrimSpace(s.Overview)
+	if s.Title == "" {
+		return fmt.Errorf("summary has no title")
+	}
+	if len(s.Sections) == 0 {
+		return fmt.Errorf("summary has no sections")
+	}
+	for i := range s.Sections {
+		sec := &s.Sections[i]
+		sec.Title = strings.TrimSpace(sec.Title)
+		if sec.Title == "" {
+			return fmt.Errorf("section %d has no title", i+1)
+		}
+		sec.Details = trimAll(sec.Details)
+	}
+	if s.KeyTerms == nil {
+		s.KeyTerms = []Term{}
+	}
+	for i := range s.KeyTerms {
+		t := &s.KeyTerms[i]
+		t.Term, t.Explanation = strings.TrimSpace(t.Term), strings.TrimSpace(t.Explanation)
+		if t.Term == "" {
+			return fmt.Errorf("key term %d is empty", i+1)
+		}
+	}
+	s.ImportantNotes = trimAll(s.ImportantNotes)
+	return nil
+}
+
+// trimAll trims every string and drops the empty ones
+func trimAll(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
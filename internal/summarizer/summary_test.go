@@ -0,0 +1,147 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const validSummary = `{
+  "title": " Deploying to Kubernetes ",
+  "overview": "How to ship the app.",
+  "sections": [
+    {"title": "Build the image", "details": ["Run **docker build**", " "]},
+    {"title": "Apply manifests", "details": ["kubectl apply -f k8s/"]}
+  ],
+  "key_terms": [{"term": "Pod", "explanation": "smallest deployable unit"}],
+  "important_notes": ["Never use the latest tag"]
+}`
+
+func TestParseSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr string
+	}{
+		{"valid", validSummary, ""},
+		{"code fence", "```json\n" + validSummary + "\n```", ""},
+		{"not json", "Here is your summary", "no JSON object"},
+		{"unknown field", `{"title": "x", "sections": [{"title": "a", "details": []}], "steps": []}`, "unknown field"},
+		{"no title", `{"title": " ", "sections": [{"title": "a", "details": []}]}`, "no title"},
+		{"no sections", `{"title": "x", "sections": []}`, "no sections"},
+		{"untitled section", `{"title": "x", "sections": [{"title": "", "details": ["d"]}]}`, "section 1 has no title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := parseSummary(tt.reply)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseSummary() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSummary() error = %v", err)
+			}
+			if sum.Title != "Deploying to Kubernetes" {
+				t.Errorf("Title = %q, want it trimmed", sum.Title)
+			}
+			if got := sum.Sections[0].Details; !reflect.DeepEqual(got, []string{"Run **docker build**"}) {
+				t.Errorf("Sections[0].Details = %q, want the blank detail dropped", got)
+			}
+		})
+	}
+}
+
+func TestSummaryMarkdown(t *testing.T) {
+	sum, err := parseSummary(validSummary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := `# Deploying to Kubernetes
+
+How to ship the app.
+
+## Build the image
+
+- Run **docker build**
+
+## Apply manifests
+
+- kubectl apply -f k8s/
+`
+
+	tests := []struct {
+		name     string
+		headings Headings
+		want     string
+	}{
+		{"vietnamese", Headings{KeyTerms: "Thuật ngữ chính", Notes: "Lưu ý quan trọng"}, body + `
+## Thuật ngữ chính
+
+- **Pod**: smallest deployable unit
+
+## Lưu ý quan trọng
+
+- Never use the latest tag
+`},
+		{"english", Headings{KeyTerms: "Key terms", Notes: "Important notes"}, body + `
+## Key terms
+
+- **Pod**: smallest deployable unit
+
+## Important notes
+
+- Never use the latest tag
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summaryMarkdown(sum, tt.headings); got != tt.want {
+				t.Errorf("summaryMarkdown() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	sum, err := parseSummary(validSummary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum.ImportantNotes = append(sum.ImportantNotes, "<script> is escaped")
+
+	base := filepath.Join(t.TempDir(), "lesson")
+	paths, err := writeSummary(sum, base, Headings{KeyTerms: "Key terms", Notes: "Important notes"})
+	if err != nil {
+		t.Fatalf("writeSummary() error = %v", err)
+	}
+	if len(paths) != 4 {
+		t.Fatalf("writeSummary() wrote %v, want docx, md, html and json", paths)
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s missing or empty: %v", path, err)
+		}
+	}
+
+	html, _ := os.ReadFile(base + ".html")
+	for _, want := range []string{"<h1>Deploying to Kubernetes</h1>", "<li>Run <strong>docker build</strong></li>", "<h2>Key terms</h2>", "<dt>Pod</dt>", "<h2>Important notes</h2>", "&lt;script&gt; is escaped"} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("HTML missing %q:\n%s", want, html)
+		}
+	}
+
+	data, _ := os.ReadFile(base + ".json")
+	var back Summary
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("decode JSON output: %v", err)
+	}
+	if !reflect.DeepEqual(&back, sum) {
+		t.Errorf("JSON output = %+v, want %+v", back, *sum)
+	}
+}
@@ -0,0 +1,80 @@
+package transcriber
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/pkg/executor"
+)
+
+// implCLI runs the whisper.cpp command line binary
+type implCLI struct {
+	cfg      config.WhisperConfig
+	executor executor.Executor
+	logger   logger.Logger
+}
+
+// Transcribe invokes whisper-cli, which writes the SRT next to the given output prefix
+// Optimized for M4 Pro with Metal acceleration and multi-threading
+func (t *implCLI) Transcribe(ctx context.Context, req Request) error {
+	// Whisper appends .srt to the output prefix itself
+	outputPrefix := strings.TrimSuffix(req.OutputPath, filepath.Ext(req.OutputPath))
+
+	threads := t.cfg.Threads
+	if req.Threads > 0 {
+		threads = req.Threads
+	}
+
+	t.logger.Info(ctx, "Starting transcription with %d threads (Metal GPU enabled): %s",
+		threads, req.AudioPath)
+
+	// Whisper arguments optimized for M4 Pro
+	// -m: Model path
+	// -f: Input audio file
+	// -osrt: Output SRT format
+	// -l: Force language (prevents hallucination)
+	// --prompt: Domain-specific keywords to improve accuracy
+	// --output-file: Output file prefix
+	// -t: Number of threads (8 for M4 Pro)
+	// -ml: Max segment length (0 = no limit, better for long videos)
+	// -mc: Max context (0 = no limit)
+	// -bo: Best of (5 = better accuracy)
+	args := []string{
+		"-m", t.cfg.ModelPath,
+		"-f", req.AudioPath,
+		"-osrt",
+		"-l", req.Language,
+		"-t", strconv.Itoa(threads),
+		"-ml", "0", // No max length limit
+		"-mc", "0", // No max context limit
+		"-bo", "5", // Best of 5 for better accuracy
+		"--prompt", req.Prompt,
+		"--output-file", outputPrefix,
+	}
+
+	// Add GPU flag if enabled (Metal acceleration on Apple Silicon)
+	if t.cfg.UseGPU {
+		// Metal is enabled by default in whisper.cpp on macOS
+		t.logger.Debug(ctx, "Metal GPU acceleration enabled")
+	}
+
+	if req.OnOutput == nil {
+		if _, err := t.executor.Execute(ctx, t.cfg.BinaryPath, args...); err != nil {
+			return fmt.Errorf("whisper transcribe: %w", err)
+		}
+		return nil
+	}
+
+	// -pp: Print progress so the caller can follow long transcriptions
+	args = append(args, "-pp")
+	if err := t.executor.Stream(ctx, "", req.OnOutput, t.cfg.BinaryPath, args...); err != nil {
+		return fmt.Errorf("whisper transcribe: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package transcriber
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// postAudio uploads the audio file as multipart/form-data along with fields and returns the
+// response body. The body is streamed from the file, so long recordings are never held in memory.
+func postAudio(ctx context.Context, client *http.Client, url string, headers map[string]string, fields map[string]string, audioPath string) ([]byte, error) {
+	audio, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio: %w", err)
+	}
+	defer audio.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, audioPath, audio))
+	}()
+	// Unblock the writer when the request ends before the body was read
+	defer pr.Close()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pr)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("post %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("post %s: status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return data, nil
+}
+
+// writeMultipart writes fields and the audio file part to mw and closes it
+func writeMultipart(mw *multipart.Writer, fields map[string]string, audioPath string, audio io.Reader) error {
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := mw.WriteField(k, v); err != nil {
+			return fmt.Errorf("write field %s: %w", k, err)
+		}
+	}
+	part, err := mw.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return fmt.Errorf("create file part: %w", err)
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return fmt.Errorf("copy audio: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("close multipart: %w", err)
+	}
+	return nil
+}
+
+// writeSRTResponse validates an SRT response body and writes it normalized to outputPath
+func writeSRTResponse(data []byte, outputPath string) error {
+	cues, err := subtitle.ParseSRT(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if len(cues) == 0 {
+		return fmt.Errorf("response contains no subtitle cues")
+	}
+	return subtitle.WriteSRTFile(outputPath, cues)
+}
@@ -0,0 +1,22 @@
+package transcriber
+
+import "context"
+
+// Transcriber defines the interface for speech-to-text backends
+type Transcriber interface {
+	// Transcribe converts the audio in req.AudioPath into an SRT file at req.OutputPath
+	Transcribe(ctx context.Context, req Request) error
+}
+
+// Request describes a single transcription job
+type Request struct {
+	AudioPath  string
+	OutputPath string
+	Language   string
+	Prompt     string
+	// Threads overrides whisper.threads when positive (CLI backend only)
+	Threads int
+	// OnOutput, when set, receives the backend's output line by line as it runs
+	// (whisper.cpp CLI progress); backends without live output ignore it
+	OnOutput func(line string)
+}
@@ -0,0 +1,45 @@
+package transcriber
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/pkg/executor"
+)
+
+const (
+	BackendCLI    = "cli"
+	BackendServer = "server"
+	BackendOpenAI = "openai"
+)
+
+// New creates the Transcriber selected by whisper.backend
+func New(cfg config.WhisperConfig, exec executor.Executor, log logger.Logger) (Transcriber, error) {
+	switch cfg.Backend {
+	case "", BackendCLI:
+		return &implCLI{
+			cfg:      cfg,
+			executor: exec,
+			logger:   log,
+		}, nil
+	case BackendServer:
+		return &implServer{
+			baseURL: cfg.ServerURL,
+			client:  &http.Client{},
+			logger:  log,
+		}, nil
+	case BackendOpenAI:
+		return &implOpenAI{
+			baseURL: cfg.APIURL,
+			model:   cfg.APIModel,
+			apiKey:  os.Getenv(cfg.APIKeyEnv),
+			client:  &http.Client{},
+			logger:  log,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown whisper backend %q", cfg.Backend)
+	}
+}
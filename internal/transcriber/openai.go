@@ -0,0 +1,58 @@
+package transcriber
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+)
+
+// OpenAIMaxUpload is the largest file /audio/transcriptions accepts. Longer recordings are
+// split into chunks of at most config.OpenAIMaxAudio before they are sent.
+const OpenAIMaxUpload = 25 << 20
+
+// implOpenAI uses an OpenAI-compatible /audio/transcriptions API
+type implOpenAI struct {
+	baseURL string
+	model   string
+	apiKey  string
+	client  *http.Client
+	logger  logger.Logger
+}
+
+// Transcribe uploads the audio and requests an SRT response
+func (t *implOpenAI) Transcribe(ctx context.Context, req Request) error {
+	url := strings.TrimRight(t.baseURL, "/") + "/audio/transcriptions"
+	info, err := os.Stat(req.AudioPath)
+	if err != nil {
+		return fmt.Errorf("openai transcribe: %w", err)
+	}
+	if info.Size() > OpenAIMaxUpload {
+		return fmt.Errorf("openai transcribe: %s is %d MB, over the %d MB upload limit; split it with whisper.chunking",
+			req.AudioPath, info.Size()>>20, OpenAIMaxUpload>>20)
+	}
+	t.logger.Info(ctx, "Starting transcription via %s (model %s): %s", t.baseURL, t.model, req.AudioPath)
+
+	var headers map[string]string
+	if t.apiKey != "" {
+		headers = map[string]string{"Authorization": "Bearer " + t.apiKey}
+	}
+
+	data, err := postAudio(ctx, t.client, url, headers, map[string]string{
+		"model":           t.model,
+		"response_format": "srt",
+		"language":        req.Language,
+		"prompt":          req.Prompt,
+	}, req.AudioPath)
+	if err != nil {
+		return fmt.Errorf("openai transcribe: %w", err)
+	}
+
+	if err := writeSRTResponse(data, req.OutputPath); err != nil {
+		return fmt.Errorf("openai transcribe: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package transcriber
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+)
+
+// implServer talks to a running whisper.cpp `server` instance
+type implServer struct {
+	baseURL string
+	client  *http.Client
+	logger  logger.Logger
+}
+
+// Transcribe posts the audio to the server's /inference endpoint and saves the SRT response
+func (t *implServer) Transcribe(ctx context.Context, req Request) error {
+	url := strings.TrimRight(t.baseURL, "/") + "/inference"
+	t.logger.Info(ctx, "Starting transcription via whisper.cpp server %s: %s", t.baseURL, req.AudioPath)
+
+	data, err := postAudio(ctx, t.client, url, nil, map[string]string{
+		"response_format": "srt",
+		"language":        req.Language,
+		"prompt":          req.Prompt,
+		"temperature":     "0.0",
+	}, req.AudioPath)
+	if err != nil {
+		return fmt.Errorf("whisper server transcribe: %w", err)
+	}
+
+	if err := writeSRTResponse(data, req.OutputPath); err != nil {
+		return fmt.Errorf("whisper server transcribe: %w", err)
+	}
+	return nil
+}
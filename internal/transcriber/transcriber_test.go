@@ -0,0 +1,149 @@
+package transcriber
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+)
+
+const fakeSRT = "1\n00:00:00,000 --> 00:00:02,000\n Hello from the fake server.\n\n"
+
+func TestHTTPBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		cfg      func(url string) config.WhisperConfig
+		wantAuth string
+	}{
+		{
+			name: "whisper.cpp server",
+			path: "/inference",
+			cfg: func(url string) config.WhisperConfig {
+				return config.WhisperConfig{Backend: BackendServer, ServerURL: url}
+			},
+		},
+		{
+			name: "openai compatible",
+			path: "/v1/audio/transcriptions",
+			cfg: func(url string) config.WhisperConfig {
+				return config.WhisperConfig{Backend: BackendOpenAI, APIURL: url + "/v1", APIModel: "whisper-1", APIKeyEnv: "TEST_WHISPER_API_KEY"}
+			},
+			wantAuth: "Bearer secret",
+		},
+	}
+
+	t.Setenv("TEST_WHISPER_API_KEY", "secret")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					http.NotFound(w, r)
+					return
+				}
+				if got := r.Header.Get("Authorization"); got != tt.wantAuth {
+					t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+				}
+				if got := r.FormValue("response_format"); got != "srt" {
+					t.Errorf("response_format = %q, want srt", got)
+				}
+				if got := r.FormValue("language"); got != "en" {
+					t.Errorf("language = %q, want en", got)
+				}
+				file, _, err := r.FormFile("file")
+				if err != nil {
+					t.Errorf("missing file part: %v", err)
+					return
+				}
+				data, _ := io.ReadAll(file)
+				if string(data) != "RIFF" {
+					t.Errorf("file content = %q, want RIFF", data)
+				}
+				io.WriteString(w, fakeSRT)
+			}))
+			defer srv.Close()
+
+			dir := t.TempDir()
+			audio := filepath.Join(dir, "audio.wav")
+			if err := os.WriteFile(audio, []byte("RIFF"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			trans, err := New(tt.cfg(srv.URL), nil, logger.New("error"))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			out := filepath.Join(dir, "audio.srt")
+			if err := trans.Transcribe(context.Background(), Request{AudioPath: audio, OutputPath: out, Language: "en"}); err != nil {
+				t.Fatalf("Transcribe() error = %v", err)
+			}
+
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != fakeSRT {
+				t.Errorf("SRT = %q, want %q", got, fakeSRT)
+			}
+		})
+	}
+}
+
+func TestHTTPBackendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "audio.wav")
+	if err := os.WriteFile(audio, []byte("RIFF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	trans, err := New(config.WhisperConfig{Backend: BackendServer, ServerURL: srv.URL}, nil, logger.New("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = trans.Transcribe(context.Background(), Request{AudioPath: audio, OutputPath: filepath.Join(dir, "audio.srt")})
+	if err == nil {
+		t.Error("Transcribe() should fail on a non-2xx response")
+	}
+}
+
+func TestOpenAIUploadLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("oversized audio was uploaded")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "audio.wav")
+	f, err := os.Create(audio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(OpenAIMaxUpload + 1); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	trans, err := New(config.WhisperConfig{Backend: BackendOpenAI, APIURL: srv.URL, APIModel: "whisper-1"}, nil, logger.New("error"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = trans.Transcribe(context.Background(), Request{AudioPath: audio, OutputPath: filepath.Join(dir, "audio.srt")})
+	if err == nil || !strings.Contains(err.Error(), "upload limit") {
+		t.Errorf("Transcribe() error = %v, want the upload limit", err)
+	}
+}
@@ -0,0 +1,14 @@
+package translator
+
+import (
+	"context"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// Translator translates subtitle cues with an LLM
+type Translator interface {
+	// Translate returns a copy of cues with Text translated to language.
+	// The result always has the same cue count, order and timing as the input.
+	Translate(ctx context.Context, cues []subtitle.Cue, language string) ([]subtitle.Cue, error)
+}
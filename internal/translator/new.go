@@ -0,0 +1,26 @@
+package translator
+
+import (
+	"github.com/nguyentantai21042004/caption-flow/internal/llm"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+)
+
+// DefaultBatchSize is the number of cues sent per request when none is configured
+const DefaultBatchSize = 50
+
+type implTranslator struct {
+	client    llm.Client
+	batchSize int
+	logger    logger.Logger
+}
+
+func New(client llm.Client, batchSize int, log logger.Logger) Translator {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &implTranslator{
+		client:    client,
+		batchSize: batchSize,
+		logger:    log,
+	}
+}
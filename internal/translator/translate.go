@@ -0,0 +1,104 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// maxAttempts is how often a batch is re-sent when the reply does not line up with the input
+const maxAttempts = 3
+
+const translatePrompt = `Translate the subtitle lines below into the language with code %q.
+
+Rules:
+- The input is a JSON array of {"id", "text"} objects, one per subtitle cue.
+- Reply with ONLY a JSON array of {"id", "text"} objects: exactly %d items, same ids, same order.
+- Translate each item on its own; never merge, split, drop or reorder items.
+- Keep line breaks ("\n") inside a text where they make sense, keep technical terms and code as-is.
+
+Input:
+%s`
+
+// item is one cue in the request and response JSON
+type item struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// Translate sends the cues in batches and checks every reply keeps count and order
+func (t *implTranslator) Translate(ctx context.Context, cues []subtitle.Cue, language string) ([]subtitle.Cue, error) {
+	out := make([]subtitle.Cue, len(cues))
+	copy(out, cues)
+
+	for start := 0; start < len(cues); start += t.batchSize {
+		end := min(start+t.batchSize, len(cues))
+		t.logger.Debug(ctx, "Translating cues %d-%d of %d to %s", start+1, end, len(cues), language)
+
+		texts, err := t.translateBatch(ctx, cues[start:end], language)
+		if err != nil {
+			return nil, fmt.Errorf("translate cues %d-%d: %w", start+1, end, err)
+		}
+		for i, text := range texts {
+			out[start+i].Text = text
+		}
+	}
+	return out, nil
+}
+
+// translateBatch returns the translated text of each cue in batch, in order
+func (t *implTranslator) translateBatch(ctx context.Context, batch []subtitle.Cue, language string) ([]string, error) {
+	items := make([]item, len(batch))
+	for i, c := range batch {
+		items[i] = item{ID: i + 1, Text: strings.Join(c.Lines(), "\n")}
+	}
+	input, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode batch: %w", err)
+	}
+	prompt := fmt.Sprintf(translatePrompt, language, len(items), input)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		reply, err := t.client.Generate(ctx, prompt)
+		if err != nil {
+			return nil, err
+		}
+
+		texts, err := parseReply(reply, len(items))
+		if err == nil {
+			return texts, nil
+		}
+		lastErr = err
+		t.logger.Warn(ctx, "Translation reply rejected (attempt %d/%d): %v", attempt, maxAttempts, err)
+	}
+	return nil, lastErr
+}
+
+// parseReply decodes the JSON array in reply and checks it has want items with ids 1..want
+func parseReply(reply string, want int) ([]string, error) {
+	first, last := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("reply contains no JSON array")
+	}
+
+	var items []item
+	if err := json.Unmarshal([]byte(reply[first:last+1]), &items); err != nil {
+		return nil, fmt.Errorf("decode reply: %w", err)
+	}
+	if len(items) != want {
+		return nil, fmt.Errorf("got %d cues back, want %d", len(items), want)
+	}
+
+	texts := make([]string, len(items))
+	for i, it := range items {
+		if it.ID != i+1 {
+			return nil, fmt.Errorf("cue %d came back as id %d", i+1, it.ID)
+		}
+		texts[i] = strings.TrimSpace(it.Text)
+	}
+	return texts, nil
+}
@@ -0,0 +1,114 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
+	"github.com/nguyentantai21042004/caption-flow/internal/llm"
+	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
+	"github.com/nguyentantai21042004/caption-flow/pkg/subtitle"
+)
+
+// newStandIn serves generateContent by upper-casing every cue; the first reply drops a cue
+func newStandIn(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			Contents []struct {
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"contents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		prompt := req.Contents[0].Parts[0].Text
+		var items []item
+		if err := json.Unmarshal([]byte(prompt[strings.Index(prompt, "Input:\n")+len("Input:\n"):]), &items); err != nil {
+			t.Errorf("decode cues from prompt: %v", err)
+			return
+		}
+		for i := range items {
+			items[i].Text = strings.ToUpper(items[i].Text)
+		}
+		if calls == 1 {
+			items = items[1:]
+		}
+		reply, _ := json.Marshal(items)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"candidates": []interface{}{map[string]interface{}{
+				"content": map[string]interface{}{"role": "model", "parts": []interface{}{map[string]string{"text": "```json\n" + string(reply) + "\n```"}}},
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestTranslate(t *testing.T) {
+	srv, calls := newStandIn(t)
+	client := llm.New(&config.Config{Gemini: config.GeminiConfig{Model: "test", BaseURL: srv.URL}}, []string{"key"}, metrics.NewNop(), logger.New("error"))
+	trans := New(client, 2, logger.New("error"))
+
+	cues := []subtitle.Cue{
+		{Index: 1, Start: 0, End: time.Second, Text: " hello"},
+		{Index: 2, Start: time.Second, End: 2 * time.Second, Text: " two\n lines"},
+		{Index: 3, Start: 2 * time.Second, End: 3 * time.Second, Text: " bye"},
+	}
+
+	got, err := trans.Translate(context.Background(), cues, "vi")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	want := []string{"HELLO", "TWO\nLINES", "BYE"}
+	if len(got) != len(want) {
+		t.Fatalf("len(Translate()) = %d, want %d", len(got), len(want))
+	}
+	for i, c := range got {
+		if c.Text != want[i] || c.Start != cues[i].Start || c.End != cues[i].End || c.Index != cues[i].Index {
+			t.Errorf("cue %d = %+v, want text %q with the original timing", i, c, want[i])
+		}
+	}
+	if *calls != 3 {
+		t.Errorf("calls = %d, want 3 (one retry after the short reply)", *calls)
+	}
+	if cues[0].Text != " hello" {
+		t.Error("Translate() modified its input")
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		wantErr bool
+	}{
+		{"plain", `[{"id": 1, "text": "a"}, {"id": 2, "text": "b"}]`, false},
+		{"fenced", "```json\n[{\"id\": 1, \"text\": \"a\"}, {\"id\": 2, \"text\": \"b\"}]\n```", false},
+		{"missing cue", `[{"id": 1, "text": "a"}]`, true},
+		{"reordered", `[{"id": 2, "text": "b"}, {"id": 1, "text": "a"}]`, true},
+		{"no json", `Sorry, I cannot help with that.`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseReply(tt.reply, 2)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseReply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import "context"
 type Watcher interface {
 	Start(ctx context.Context) error
 	Stop() error
+	// Submit queues a file for processing outside of fsnotify events (e.g. jobs resumed after a restart)
+	Submit(ctx context.Context, filePath string)
 }
 
 // EventHandler is a function that handles file events
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package watcher
+
+import "os"
+
+// canLockExclusive reports whether path can be opened for writing; on Windows this
+// fails with a sharing violation while another process still has the file open
+func canLockExclusive(path string) bool {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
@@ -4,17 +4,21 @@ import (
 	"fmt"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
 	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
 )
 
 // New creates a new Watcher instance with concurrency control
-func New(inputDir string, handler EventHandler, log logger.Logger, maxConcurrent int) (Watcher, error) {
+func New(inputDir string, cfg config.WatcherConfig, handler EventHandler, m metrics.Metrics, log logger.Logger, maxConcurrent int) (Watcher, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, fmt.Errorf("create watcher: %w", err)
 	}
 
-	if err := watcher.Add(inputDir); err != nil {
+	// Files already in the input tree at startup are left alone, as before;
+	// unfinished ones are resumed from the job store instead
+	if _, err := addRecursive(watcher, inputDir); err != nil {
 		watcher.Close()
 		return nil, fmt.Errorf("add watch path: %w", err)
 	}
@@ -26,9 +30,12 @@ func New(inputDir string, handler EventHandler, log logger.Logger, maxConcurrent
 
 	return &implWatcher{
 		inputDir:      inputDir,
+		cfg:           cfg,
 		handler:       handler,
+		metrics:       m,
 		logger:        log,
 		watcher:       watcher,
+		stability:     newStabilityTracker(cfg.StableWindow, cfg.ExclusiveOpen),
 		maxConcurrent: maxConcurrent,
 		semaphore:     make(chan struct{}, maxConcurrent),
 	}, nil
@@ -0,0 +1,35 @@
+package watcher
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// addRecursive registers root and every non-hidden subfolder below it with fsnotify.
+// It returns the video files already present, which produce no CREATE events of their own
+// (e.g. a whole course folder moved into the input folder at once).
+func addRecursive(watcher *fsnotify.Watcher, root string) ([]string, error) {
+	var existing []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		if isVideoFile(path) {
+			existing = append(existing, path)
+		}
+		return nil
+	})
+	return existing, err
+}
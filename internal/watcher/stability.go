@@ -0,0 +1,82 @@
+package watcher
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// fileState is the last observed size/mtime of a file that is still being written
+type fileState struct {
+	size    int64
+	modTime time.Time
+	since   time.Time // when size/mtime last changed or a write event arrived
+}
+
+// stabilityTracker decides when a detected file has finished being written.
+// A file is ready once its size and mtime have not changed for the whole window
+// and, when exclusive is set, it can be locked exclusively (no writer holds it).
+// It is only used from the watcher loop goroutine and is not safe for concurrent use.
+type stabilityTracker struct {
+	window    time.Duration
+	exclusive bool
+	files     map[string]*fileState
+}
+
+func newStabilityTracker(window time.Duration, exclusive bool) *stabilityTracker {
+	return &stabilityTracker{
+		window:    window,
+		exclusive: exclusive,
+		files:     make(map[string]*fileState),
+	}
+}
+
+// touch records activity on path (create or write event), restarting its quiet window
+func (t *stabilityTracker) touch(path string, now time.Time) {
+	st, ok := t.files[path]
+	if !ok {
+		st = &fileState{size: -1}
+		t.files[path] = st
+	}
+	st.since = now
+}
+
+// pending reports how many files are still waiting to settle
+func (t *stabilityTracker) pending() int {
+	return len(t.files)
+}
+
+// ready returns the files that have been quiescent for the window and stops tracking them.
+// Files that disappeared are dropped silently.
+func (t *stabilityTracker) ready(now time.Time) []string {
+	var out []string
+	for path, st := range t.files {
+		info, err := os.Stat(path)
+		if err != nil {
+			delete(t.files, path)
+			continue
+		}
+
+		if info.Size() != st.size || !info.ModTime().Equal(st.modTime) {
+			st.size = info.Size()
+			st.modTime = info.ModTime()
+			st.since = now
+			continue
+		}
+
+		// An empty file is usually a copy that has not started streaming data yet
+		if info.Size() == 0 || now.Sub(st.since) < t.window {
+			continue
+		}
+
+		if t.exclusive && !canLockExclusive(path) {
+			continue
+		}
+
+		delete(t.files, path)
+		out = append(out, path)
+	}
+
+	sort.Strings(out)
+	return out
+}
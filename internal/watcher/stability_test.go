@@ -0,0 +1,79 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStabilityTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path, []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := newStabilityTracker(2*time.Second, false)
+	now := time.Now()
+	tracker.touch(path, now)
+
+	// First check only records size/mtime
+	if got := tracker.ready(now); len(got) != 0 {
+		t.Fatalf("ready() = %v before the window elapsed", got)
+	}
+
+	// File keeps growing: window restarts
+	if err := os.WriteFile(path, []byte("partial + more data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(3 * time.Second)
+	if got := tracker.ready(now); len(got) != 0 {
+		t.Fatalf("ready() = %v while the file was still changing", got)
+	}
+
+	// A write event also restarts the window
+	tracker.touch(path, now.Add(time.Second))
+	if got := tracker.ready(now.Add(2 * time.Second)); len(got) != 0 {
+		t.Fatalf("ready() = %v right after a write event", got)
+	}
+
+	got := tracker.ready(now.Add(4 * time.Second))
+	if len(got) != 1 || got[0] != path {
+		t.Fatalf("ready() = %v, want [%s]", got, path)
+	}
+	if tracker.pending() != 0 {
+		t.Errorf("pending() = %d, want 0 after dispatch", tracker.pending())
+	}
+}
+
+func TestStabilityTrackerDropsRemovedAndWaitsOnEmpty(t *testing.T) {
+	dir := t.TempDir()
+	removed := filepath.Join(dir, "removed.mp4")
+	empty := filepath.Join(dir, "empty.mp4")
+	for _, p := range []string{removed, empty} {
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tracker := newStabilityTracker(time.Second, false)
+	now := time.Now()
+	tracker.touch(removed, now)
+	tracker.touch(empty, now)
+	tracker.ready(now)
+
+	if err := os.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := tracker.ready(now.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("ready() = %v, want nothing for removed/empty files", got)
+	}
+	if tracker.pending() != 1 {
+		t.Errorf("pending() = %d, want only the empty file left", tracker.pending())
+	}
+}
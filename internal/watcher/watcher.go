@@ -3,20 +3,26 @@ package watcher
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/nguyentantai21042004/caption-flow/internal/config"
 	"github.com/nguyentantai21042004/caption-flow/internal/logger"
+	"github.com/nguyentantai21042004/caption-flow/internal/metrics"
 )
 
 type implWatcher struct {
 	inputDir      string
+	cfg           config.WatcherConfig
 	handler       EventHandler
+	metrics       metrics.Metrics
 	logger        logger.Logger
 	watcher       *fsnotify.Watcher
+	stability     *stabilityTracker
 	maxConcurrent int
 	semaphore     chan struct{}
 	wg            sync.WaitGroup
@@ -25,8 +31,12 @@ type implWatcher struct {
 // Start begins monitoring the input directory for new video files
 // Optimized for M4 Pro with concurrent processing support
 func (w *implWatcher) Start(ctx context.Context) error {
-	w.logger.Info(ctx, "File watcher started (max concurrent: %d). Monitoring: %s", w.maxConcurrent, w.inputDir)
+	w.logger.Info(ctx, "File watcher started (max concurrent: %d). Monitoring recursively: %s", w.maxConcurrent, w.inputDir)
 	w.logger.Info(ctx, "Supported formats: .mp4, .mov, .avi, .mkv, .webm, .m4v, .flv")
+	w.logger.Info(ctx, "Files are dispatched after %s without changes (exclusive open check: %v)", w.cfg.StableWindow, w.cfg.ExclusiveOpen)
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
@@ -41,33 +51,34 @@ func (w *implWatcher) Start(ctx context.Context) error {
 				return fmt.Errorf("watcher events channel closed")
 			}
 
-			// Only process CREATE events
-			if event.Op&fsnotify.Create == fsnotify.Create {
-				if w.isVideoFile(event.Name) {
-					w.logger.Info(ctx, "New video detected: %s", event.Name)
-
-					// Small delay to ensure file is fully written
-					time.Sleep(500 * time.Millisecond)
-
-					// Acquire semaphore slot (blocks if max concurrent reached)
-					select {
-					case w.semaphore <- struct{}{}:
-						w.wg.Add(1)
-						// Handle the file in a goroutine with concurrency control
-						go func(filePath string) {
-							defer w.wg.Done()
-							defer func() { <-w.semaphore }() // Release semaphore
-
-							if err := w.handler(ctx, filePath); err != nil {
-								w.logger.Error(ctx, "Failed to process %s: %v", filePath, err)
-							}
-						}(event.Name)
-					case <-ctx.Done():
-						return ctx.Err()
-					}
-				} else {
+			// fsnotify does not expose CLOSE_WRITE portably, so every CREATE/WRITE
+			// restarts the quiet window and the ticker decides when the file is done
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			if event.Has(fsnotify.Create) && w.isNewDir(event.Name) {
+				w.watchNewDir(ctx, event.Name)
+				continue
+			}
+			if !isVideoFile(event.Name) {
+				if event.Has(fsnotify.Create) {
 					w.logger.Debug(ctx, "Ignoring non-video file: %s", event.Name)
 				}
+				continue
+			}
+
+			if event.Has(fsnotify.Create) {
+				w.logger.Info(ctx, "New video detected, waiting for it to finish writing: %s", event.Name)
+			}
+			w.stability.touch(event.Name, time.Now())
+
+		case <-ticker.C:
+			if w.stability.pending() == 0 {
+				continue
+			}
+			for _, filePath := range w.stability.ready(time.Now()) {
+				w.logger.Info(ctx, "Video is ready for processing: %s", filePath)
+				w.Submit(ctx, filePath)
 			}
 
 		case err, ok := <-w.watcher.Errors:
@@ -79,13 +90,63 @@ func (w *implWatcher) Start(ctx context.Context) error {
 	}
 }
 
+// Submit dispatches a file through the same concurrency limit as detected videos.
+// It does not block; the file waits for a free slot in the background.
+func (w *implWatcher) Submit(ctx context.Context, filePath string) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		select {
+		case w.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		w.metrics.SemaphoreAcquired()
+		defer func() {
+			<-w.semaphore // Release semaphore
+			w.metrics.SemaphoreReleased()
+		}()
+
+		if err := w.handler(ctx, filePath); err != nil {
+			w.logger.Error(ctx, "Failed to process %s: %v", filePath, err)
+		}
+	}()
+}
+
 // Stop closes the file watcher
 func (w *implWatcher) Stop() error {
 	return w.watcher.Close()
 }
 
+// isNewDir reports whether a created path is a (non-hidden) directory
+func (w *implWatcher) isNewDir(path string) bool {
+	if strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// watchNewDir starts watching a subfolder created (or moved in) after startup,
+// and queues the videos it already contains
+func (w *implWatcher) watchNewDir(ctx context.Context, dir string) {
+	existing, err := addRecursive(w.watcher, dir)
+	if err != nil {
+		w.logger.Error(ctx, "Failed to watch new folder %s: %v", dir, err)
+		return
+	}
+	w.logger.Info(ctx, "Watching new folder: %s", dir)
+
+	now := time.Now()
+	for _, path := range existing {
+		w.logger.Info(ctx, "New video detected, waiting for it to finish writing: %s", path)
+		w.stability.touch(path, now)
+	}
+}
+
 // isVideoFile checks if the file has a supported video extension
-func (w *implWatcher) isVideoFile(path string) bool {
+func isVideoFile(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	supportedFormats := []string{".mp4", ".mov", ".avi", ".mkv", ".webm", ".m4v", ".flv"}
 
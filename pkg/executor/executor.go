@@ -3,55 +3,78 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 )
 
-type implExecutor struct{}
+type implExecutor struct {
+	opts Options
+}
 
-// New creates a new Executor instance
+// New creates a new Executor instance without timeouts or limits
 func New() Executor {
-	return &implExecutor{}
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates an Executor that applies opts to every command
+func NewWithOptions(opts Options) Executor {
+	if opts.KillGrace <= 0 {
+		opts.KillGrace = DefaultKillGrace
+	}
+	return &implExecutor{opts: opts}
 }
 
 // Execute runs an external command with the given arguments
 func (e *implExecutor) Execute(ctx context.Context, name string, args ...string) (string, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
+	return e.ExecuteInDir(ctx, "", name, args...)
+}
+
+// ExecuteInDir runs an external command in a specific working directory
+func (e *implExecutor) ExecuteInDir(ctx context.Context, dir string, name string, args ...string) (string, error) {
+	ctx, cancel := e.commandContext(ctx)
+	defer cancel()
+
+	cmd := e.command(ctx, dir, name, args...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		// Include stderr in error message for debugging
-		stderrStr := strings.TrimSpace(stderr.String())
-		if stderrStr != "" {
-			return "", fmt.Errorf("command '%s' failed: %w\nstderr: %s", name, err, stderrStr)
-		}
-		return "", fmt.Errorf("command '%s' failed: %w", name, err)
+		return "", commandError(ctx, name, err, stderr.String())
 	}
 
 	return stdout.String(), nil
 }
 
-// ExecuteInDir runs an external command in a specific working directory
-func (e *implExecutor) ExecuteInDir(ctx context.Context, dir string, name string, args ...string) (string, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
-	cmd.Dir = dir // Set working directory
+// commandContext applies the per-command timeout
+func (e *implExecutor) commandContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := commandTimeout(ctx, e.opts.Timeout); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+// command builds cmd with the configured limits, running in its own process group
+func (e *implExecutor) command(ctx context.Context, dir, name string, args ...string) *exec.Cmd {
+	path, args := e.opts.wrap(name, args)
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = dir // Set working directory
+	setProcessGroup(cmd, e.opts.KillGrace)
+	return cmd
+}
 
-	if err := cmd.Run(); err != nil {
-		// Include stderr in error message for debugging
-		stderrStr := strings.TrimSpace(stderr.String())
-		if stderrStr != "" {
-			return "", fmt.Errorf("command '%s' failed: %w\nstderr: %s", name, err, stderrStr)
-		}
-		return "", fmt.Errorf("command '%s' failed: %w", name, err)
+// commandError describes a failed command, including stderr for debugging
+// and whether it was stopped by its timeout
+func commandError(ctx context.Context, name string, err error, stderr string) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out: %w", err)
 	}
-
-	return stdout.String(), nil
+	stderrStr := strings.TrimSpace(stderr)
+	if stderrStr != "" {
+		return fmt.Errorf("command '%s' failed: %w\nstderr: %s", name, err, stderrStr)
+	}
+	return fmt.Errorf("command '%s' failed: %w", name, err)
 }
@@ -0,0 +1,49 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutKillsProcessGroup(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "survived")
+	e := NewWithOptions(Options{KillGrace: 100 * time.Millisecond})
+
+	// The grandchild ignores SIGTERM, so only the group SIGKILL after the grace period stops it
+	ctx := WithTimeout(context.Background(), 200*time.Millisecond)
+	started := time.Now()
+	_, err := e.Execute(ctx, "sh", "-c", `sh -c 'trap "" TERM; sleep 1; touch `+marker+`' & wait`)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Execute() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(started); elapsed > 900*time.Millisecond {
+		t.Errorf("Execute() returned after %v, want it bounded by timeout + grace", elapsed)
+	}
+
+	time.Sleep(1200 * time.Millisecond)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("grandchild survived the process group kill")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	e := NewWithOptions(Options{Nice: 5, MaxCPUTime: time.Minute})
+	out, err := e.Execute(context.Background(), "sh", "-c", "ulimit -t; nice")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 || fields[0] != "60" {
+		t.Fatalf("limits output = %q, want CPU limit 60", out)
+	}
+	base, _ := New().Execute(context.Background(), "nice")
+	if fields[1] == strings.TrimSpace(base) {
+		t.Errorf("niceness = %s, want it raised from %s", fields[1], strings.TrimSpace(base))
+	}
+}
@@ -6,4 +6,8 @@ import "context"
 type Executor interface {
 	Execute(ctx context.Context, name string, args ...string) (string, error)
 	ExecuteInDir(ctx context.Context, dir string, name string, args ...string) (string, error)
+	// Stream runs the command in dir (the current directory when empty) and calls onLine for
+	// every line of stdout and stderr as it is written. Output reaches the caller only
+	// through onLine; a failure's error includes the tail of stderr.
+	Stream(ctx context.Context, dir string, onLine func(line string), name string, args ...string) error
 }
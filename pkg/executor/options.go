@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultKillGrace is how long a canceled command gets between SIGTERM and SIGKILL
+const DefaultKillGrace = 10 * time.Second
+
+// Options bound every command an Executor runs
+type Options struct {
+	// Timeout applies to commands whose context carries no timeout from WithTimeout; 0 disables
+	Timeout time.Duration
+	// KillGrace is the delay between SIGTERM and SIGKILL of the command's process group
+	KillGrace time.Duration
+	// Nice is added to the niceness of each command (0 keeps the current priority)
+	Nice int
+	// MaxCPUTime is the CPU time limit (RLIMIT_CPU) of each process; 0 means unlimited
+	MaxCPUTime time.Duration
+	// MaxMemory is the address space limit (RLIMIT_AS) in bytes; 0 means unlimited.
+	// Not enforced on macOS, where the kernel ignores it.
+	MaxMemory int64
+}
+
+type timeoutKey struct{}
+
+// WithTimeout makes every command run with ctx time out after d, each command counting
+// from its own start. Callers use it to scale timeouts with the media being processed.
+func WithTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, d)
+}
+
+// commandTimeout returns the timeout set by WithTimeout, or fallback
+func commandTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	return fallback
+}
+
+// limited reports whether commands must run through the limits wrapper
+func (o Options) limited() bool {
+	return o.Nice != 0 || o.MaxCPUTime > 0 || o.MaxMemory > 0
+}
+
+// wrap runs name through sh so ulimit and nice apply to it and everything it spawns.
+// The command itself is passed as positional arguments, so nothing is re-quoted.
+func (o Options) wrap(name string, args []string) (string, []string) {
+	if !o.limited() {
+		return name, args
+	}
+
+	script := ""
+	if o.MaxCPUTime > 0 {
+		script += "ulimit -t " + strconv.FormatInt(int64((o.MaxCPUTime+time.Second-1)/time.Second), 10) + " && "
+	}
+	if o.MaxMemory > 0 {
+		// RLIMIT_AS cannot be lowered on macOS; run unlimited there instead of failing
+		script += fmt.Sprintf("{ ulimit -v %d 2>/dev/null || true; } && ", o.MaxMemory/1024)
+	}
+	if o.Nice != 0 {
+		script += "exec nice -n " + strconv.Itoa(o.Nice) + ` "$@"`
+	} else {
+		script += `exec "$@"`
+	}
+	return "/bin/sh", append([]string{"-c", script, "sh", name}, args...)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package executor
+
+import (
+	"os/exec"
+	"time"
+)
+
+// setProcessGroup kills only the direct child after grace; process groups are unix-only
+func setProcessGroup(cmd *exec.Cmd, grace time.Duration) {
+	cmd.WaitDelay = grace
+}
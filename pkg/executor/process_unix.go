@@ -0,0 +1,29 @@
+//go:build unix
+
+package executor
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts cmd in its own process group so cancellation reaches the
+// children it spawns: SIGTERM to the whole group, SIGKILL after grace
+func setProcessGroup(cmd *exec.Cmd, grace time.Duration) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
+			if errors.Is(err, syscall.ESRCH) {
+				return nil
+			}
+			return err
+		}
+		time.AfterFunc(grace, func() { syscall.Kill(-pgid, syscall.SIGKILL) })
+		return nil
+	}
+	// Stop waiting for pipes held open by stragglers once the group has been killed
+	cmd.WaitDelay = grace + time.Second
+}
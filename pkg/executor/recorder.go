@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Command is an external command captured by a Recorder
+type Command struct {
+	Dir  string
+	Name string
+	Args []string
+}
+
+// String renders the command as a copy-pasteable shell line
+func (c Command) String() string {
+	parts := make([]string, 0, len(c.Args)+3)
+	if c.Dir != "" {
+		parts = append(parts, "cd", shellQuote(c.Dir), "&&")
+	}
+	parts = append(parts, shellQuote(c.Name))
+	for _, arg := range c.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote wraps s in single quotes when it contains anything a POSIX shell would interpret
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,+@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Recorder is an Executor that records commands instead of running them
+type Recorder interface {
+	Executor
+	// Take returns the commands recorded since the last call and clears the record
+	Take() []Command
+}
+
+type implRecorder struct {
+	mu       sync.Mutex
+	commands []Command
+}
+
+// NewRecorder creates an Executor for dry runs: every command succeeds with empty output
+func NewRecorder() Recorder {
+	return &implRecorder{}
+}
+
+func (r *implRecorder) Execute(ctx context.Context, name string, args ...string) (string, error) {
+	return r.ExecuteInDir(ctx, "", name, args...)
+}
+
+func (r *implRecorder) ExecuteInDir(ctx context.Context, dir string, name string, args ...string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, Command{Dir: dir, Name: name, Args: append([]string(nil), args...)})
+	return "", nil
+}
+
+// Stream records the command; onLine is never called
+func (r *implRecorder) Stream(ctx context.Context, dir string, onLine func(line string), name string, args ...string) error {
+	_, err := r.ExecuteInDir(ctx, dir, name, args...)
+	return err
+}
+
+func (r *implRecorder) Take() []Command {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	commands := r.commands
+	r.commands = nil
+	return commands
+}
@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	ctx := context.Background()
+
+	if _, err := rec.Execute(ctx, "ffmpeg", "-i", "my video.mp4", "-y", "out.wav"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.ExecuteInDir(ctx, "/tmp/burn", "ffmpeg", "-vf", "subtitles=subtitle.ass", "it's.mp4"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rec.Take()
+	want := []string{
+		"ffmpeg -i 'my video.mp4' -y out.wav",
+		`cd /tmp/burn && ffmpeg -vf subtitles=subtitle.ass 'it'\''s.mp4'`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Take() returned %d commands, want %d", len(got), len(want))
+	}
+	for i, c := range got {
+		if c.String() != want[i] {
+			t.Errorf("command %d = %s, want %s", i, c, want[i])
+		}
+	}
+	if len(rec.Take()) != 0 {
+		t.Error("Take() should clear the record")
+	}
+}
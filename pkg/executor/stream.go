@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// stderrTail is how much of a streamed command's stderr is kept for its error message
+const stderrTail = 64 * 1024
+
+// Stream runs an external command and hands every output line to onLine as it arrives.
+// Output is not kept beyond the last stderrTail bytes of stderr, so long runs with
+// progress output use constant memory.
+func (e *implExecutor) Stream(ctx context.Context, dir string, onLine func(line string), name string, args ...string) error {
+	ctx, cancel := e.commandContext(ctx)
+	defer cancel()
+
+	cmd := e.command(ctx, dir, name, args...)
+
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("command '%s' stdout: %w", name, err)
+	}
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("command '%s' stderr: %w", name, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("command '%s' failed: %w", name, err)
+	}
+
+	// onLine is called from both readers, so serialize it
+	var mu sync.Mutex
+	emit := func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		onLine(line)
+	}
+
+	stderr := &tailBuffer{max: stderrTail}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		scanLines(stdoutPipe, emit)
+	}()
+	go func() {
+		defer wg.Done()
+		scanLines(io.TeeReader(stderrPipe, stderr), emit)
+	}()
+	wg.Wait()
+
+	if err := cmd.Wait(); err != nil {
+		return commandError(ctx, name, err, stderr.String())
+	}
+	return nil
+}
+
+// scanLines calls emit per line of r. Carriage returns also end a line, since progress
+// meters redraw a single line with \r.
+func scanLines(r io.Reader, emit func(string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	})
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			emit(line)
+		}
+	}
+	// Drain the rest so the process never blocks on a full pipe
+	io.Copy(io.Discard, r)
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > t.max {
+		p = p[len(p)-t.max:]
+		t.truncated = true
+	}
+	if over := len(t.buf) + len(p) - t.max; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+		t.truncated = true
+	}
+	t.buf = append(t.buf, p...)
+	return n, nil
+}
+
+// String returns the kept bytes, starting at a line boundary once older output was dropped
+func (t *tailBuffer) String() string {
+	buf := t.buf
+	if t.truncated {
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			buf = buf[i+1:]
+		}
+	}
+	return string(buf)
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	var lines []string
+	err := New().Stream(context.Background(), "", func(line string) {
+		lines = append(lines, line)
+	}, "sh", "-c", `printf 'progress=continue\nout_time_us=5\r10%%\n'; echo oops >&2`)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+
+	want := map[string]bool{"progress=continue": true, "out_time_us=5": true, "10%": true, "oops": true}
+	if len(lines) != len(want) {
+		t.Fatalf("lines = %q, want %d lines", lines, len(want))
+	}
+	for _, line := range lines {
+		if !want[line] {
+			t.Errorf("unexpected line %q", line)
+		}
+	}
+
+	if err := New().Stream(context.Background(), "", func(string) {}, "sh", "-c", "echo broken >&2; exit 3"); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Stream() error = %v, want it to include stderr", err)
+	}
+}
+
+func TestStreamStderrTail(t *testing.T) {
+	// 200KB of progress lines on stderr; only the tail ends up in the error
+	script := `i=0; while [ $i -lt 20000 ]; do echo "frame=$i fps=30" >&2; i=$((i+1)); done; echo "last error" >&2; exit 1`
+	err := New().Stream(context.Background(), "", func(string) {}, "sh", "-c", script)
+	if err == nil {
+		t.Fatal("Stream() error = nil, want failure")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "last error") {
+		t.Errorf("error is missing the end of stderr")
+	}
+	if strings.Contains(msg, "frame=0 ") {
+		t.Errorf("error kept the start of stderr")
+	}
+	if len(msg) > stderrTail+200 {
+		t.Errorf("error is %d bytes, want at most the %d byte tail", len(msg), stderrTail)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"fits", []string{"a\n", "b\n"}, "a\nb\n"},
+		{"drops old lines", []string{"first\n", "second\n", "third\n"}, "third\n"},
+		{"single large write", []string{"0123456789\nabc\n"}, "abc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &tailBuffer{max: 8}
+			for _, w := range tt.writes {
+				if n, err := buf.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write() = %d, %v", n, err)
+				}
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
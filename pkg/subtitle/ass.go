@@ -0,0 +1,51 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// assHeader matches what `ffmpeg -i x.srt x.ass` produces; the style line is filled in
+// from a Style, which defaults to the libass defaults
+const assHeader = `[Script Info]
+ScriptType: v4.00+
+PlayResX: 384
+PlayResY: 288
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+%s
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+var assEscaper = strings.NewReplacer("{", `\{`, "}", `\}`)
+
+// FormatASS serializes cues as an Advanced SubStation Alpha script (H:MM:SS.cc timestamps)
+func FormatASS(cues []Cue) string {
+	return FormatASSWithStyle(cues, DefaultStyle())
+}
+
+// FormatASSWithStyle is FormatASS with a custom Default style, used for burned-in captions
+func FormatASSWithStyle(cues []Cue, style Style) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, assHeader, style.line())
+	for _, c := range cues {
+		fmt.Fprintf(&sb, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", formatASSTimestamp(c.Start), formatASSTimestamp(c.End),
+			assEscaper.Replace(strings.Join(c.Lines(), `\N`)))
+	}
+	return sb.String()
+}
+
+// formatASSTimestamp renders d as H:MM:SS.cc (centisecond precision)
+func formatASSTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
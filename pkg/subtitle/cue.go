@@ -0,0 +1,51 @@
+package subtitle
+
+import (
+	"strings"
+	"time"
+)
+
+// Cue is a single timed subtitle entry
+type Cue struct {
+	Index int
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Duration returns how long the cue stays on screen
+func (c Cue) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// Lines returns the cue text split into display lines, trimmed of surrounding whitespace
+func (c Cue) Lines() []string {
+	var lines []string
+	for _, line := range strings.Split(c.Text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Renumber rewrites cue indices sequentially starting at 1
+func Renumber(cues []Cue) {
+	for i := range cues {
+		cues[i].Index = i + 1
+	}
+}
+
+// PlainText joins the dialogue of all cues, one cue per line, without indices or timestamps
+func PlainText(cues []Cue) string {
+	var sb strings.Builder
+	for _, c := range cues {
+		text := strings.Join(c.Lines(), " ")
+		if text == "" {
+			continue
+		}
+		sb.WriteString(text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
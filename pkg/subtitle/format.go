@@ -0,0 +1,79 @@
+package subtitle
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Format identifies a subtitle file format
+type Format string
+
+const (
+	SRT  Format = "srt"
+	VTT  Format = "vtt"
+	ASS  Format = "ass"
+	TTML Format = "ttml"
+	SBV  Format = "sbv"
+	JSON Format = "json"
+)
+
+// Formats lists every supported output format
+var Formats = []Format{SRT, VTT, ASS, TTML, SBV, JSON}
+
+// ParseFormat resolves a format name (case-insensitive, common aliases accepted)
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), ".")) {
+	case "srt", "subrip":
+		return SRT, nil
+	case "vtt", "webvtt":
+		return VTT, nil
+	case "ass", "ssa":
+		return ASS, nil
+	case "ttml", "dfxp", "xml":
+		return TTML, nil
+	case "sbv":
+		return SBV, nil
+	case "json":
+		return JSON, nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle format %q", name)
+	}
+}
+
+// Extension returns the file extension for the format, including the dot
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Encode serializes cues in the given format
+func Encode(f Format, cues []Cue) ([]byte, error) {
+	switch f {
+	case SRT:
+		return []byte(FormatSRT(cues)), nil
+	case VTT:
+		return []byte(FormatVTT(cues)), nil
+	case ASS:
+		return []byte(FormatASS(cues)), nil
+	case TTML:
+		return []byte(FormatTTML(cues)), nil
+	case SBV:
+		return []byte(FormatSBV(cues)), nil
+	case JSON:
+		return FormatJSON(cues)
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format %q", f)
+	}
+}
+
+// WriteFile encodes cues in format f and writes them to path
+func WriteFile(path string, f Format, cues []Cue) error {
+	data, err := Encode(f, cues)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", f, err)
+	}
+	return nil
+}
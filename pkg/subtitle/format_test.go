@@ -0,0 +1,122 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	cues := []Cue{
+		{Index: 1, Start: 1500 * time.Millisecond, End: time.Hour + 2*time.Second + 345*time.Millisecond, Text: " Tom & Jerry\n <b>{bold}</b>"},
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{SRT, "1\n00:00:01,500 --> 01:00:02,345\n Tom & Jerry\n <b>{bold}</b>\n\n"},
+		{VTT, "WEBVTT\n\n1\n00:00:01.500 --> 01:00:02.345\nTom &amp; Jerry\n&lt;b&gt;{bold}&lt;/b&gt;\n\n"},
+		{SBV, "0:00:01.500,1:00:02.345\nTom & Jerry\n<b>{bold}</b>\n\n"},
+		{ASS, "Dialogue: 0,0:00:01.50,1:00:02.34,Default,,0,0,0,,Tom & Jerry\\N<b>\\{bold\\}</b>\n"},
+		{TTML, `<p begin="00:00:01.500" end="01:00:02.345">Tom &amp; Jerry<br/>&lt;b&gt;{bold}&lt;/b&gt;</p>`},
+		{JSON, `"start": 1.5,`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			data, err := Encode(tt.format, cues)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("Encode() = %q, want it to contain %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"srt", SRT, false},
+		{"WebVTT", VTT, false},
+		{".ass", ASS, false},
+		{"dfxp", TTML, false},
+		{"docx", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatASSWithStyle(t *testing.T) {
+	if !strings.Contains(FormatASS(nil), "Style: Default,Arial,16,&Hffffff,&Hffffff,&H0,&H0,0,0,0,0,100,100,0,0,1,1,0,2,10,10,10,0\n") {
+		t.Errorf("default style changed:\n%s", FormatASS(nil))
+	}
+
+	style := DefaultStyle()
+	style.FontName = "Roboto"
+	style.FontSize = 22
+	style.Bold = true
+	style.BorderStyle = BorderOpaqueBox
+	style.Alignment = 8
+	got := FormatASSWithStyle(nil, style)
+	if want := "Style: Default,Roboto,22,&Hffffff,&Hffffff,&H0,&H0,-1,0,0,0,100,100,0,0,3,1,0,8,10,10,10,0\n"; !strings.Contains(got, want) {
+		t.Errorf("FormatASSWithStyle() = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"#FFCC00", "&H0000CCFF", false},
+		{"#00000080", "&H7F000000", false},
+		{"&H00FFFFFF", "&H00FFFFFF", false},
+		{"yellow", "", true},
+		{"#12345", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseColor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageTag(t *testing.T) {
+	tests := map[string]string{
+		"en":    "eng",
+		"VI":    "vie",
+		"pt-BR": "por",
+		"jpn":   "jpn",
+		"auto":  "und",
+		"":      "und",
+	}
+	for in, want := range tests {
+		if got := LanguageTag(in); got != want {
+			t.Errorf("LanguageTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
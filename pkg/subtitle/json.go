@@ -0,0 +1,38 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonCue is the JSON representation of a cue; times are in seconds
+type jsonCue struct {
+	Index int     `json:"index"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// FormatJSON serializes cues as a JSON array with start/end in seconds
+func FormatJSON(cues []Cue) ([]byte, error) {
+	out := make([]jsonCue, len(cues))
+	for i, c := range cues {
+		index := c.Index
+		if index <= 0 {
+			index = i + 1
+		}
+		out[i] = jsonCue{
+			Index: index,
+			Start: c.Start.Seconds(),
+			End:   c.End.Seconds(),
+			Text:  strings.Join(c.Lines(), "\n"),
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,29 @@
+package subtitle
+
+import "strings"
+
+// iso6392 maps ISO 639-1 codes to the ISO 639-2/B codes expected in MP4/MKV stream metadata
+var iso6392 = map[string]string{
+	"ar": "ara", "cs": "cze", "da": "dan", "de": "ger", "el": "gre", "en": "eng",
+	"es": "spa", "fi": "fin", "fr": "fre", "he": "heb", "hi": "hin", "hu": "hun",
+	"id": "ind", "it": "ita", "ja": "jpn", "km": "khm", "ko": "kor", "lo": "lao",
+	"ms": "may", "nl": "dut", "no": "nor", "pl": "pol", "pt": "por", "ro": "rum",
+	"ru": "rus", "sv": "swe", "th": "tha", "tl": "tgl", "tr": "tur", "uk": "ukr",
+	"vi": "vie", "zh": "chi",
+}
+
+// LanguageTag returns the three-letter ISO 639-2 code for a whisper-style language code
+// ("en", "vi", "pt-BR", ...). Unknown or "auto" languages map to "und".
+func LanguageTag(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if tag, ok := iso6392[code]; ok {
+		return tag
+	}
+	if len(code) == 3 && code != "und" {
+		return code
+	}
+	return "und"
+}
@@ -0,0 +1,251 @@
+package subtitle
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Layout holds readability limits for cues. Zero fields disable the matching step.
+type Layout struct {
+	MaxCharsPerLine int
+	MaxLines        int           // lines per cue
+	MaxDuration     time.Duration // longer cues are split
+	MinDuration     time.Duration // shorter cues are extended when the next cue allows it
+	MinGap          time.Duration // pause kept between consecutive cues
+	MergeBelow      time.Duration // cues shorter than this are merged into a neighbour that has room
+	Balance         bool          // even out line lengths instead of filling the first line
+}
+
+// Apply returns cues split, merged, retimed and wrapped according to the layout, renumbered from 1
+func (l Layout) Apply(cues []Cue) []Cue {
+	var out []Cue
+	for _, c := range cues {
+		c.Text = strings.Join(strings.Fields(c.Text), " ")
+		if c.Text == "" {
+			continue
+		}
+		out = append(out, l.split(c)...)
+	}
+	out = l.merge(out)
+	l.retime(out)
+	for i := range out {
+		out[i].Text = strings.Join(Wrap(out[i].Text, l.MaxCharsPerLine, l.Balance), "\n")
+	}
+	Renumber(out)
+	return out
+}
+
+// fits reports whether text wraps into MaxLines and lasts no longer than MaxDuration
+func (l Layout) fits(text string, d time.Duration) bool {
+	if l.MaxDuration > 0 && d > l.MaxDuration {
+		return false
+	}
+	return l.MaxLines <= 0 || len(Wrap(text, l.MaxCharsPerLine, false)) <= l.MaxLines
+}
+
+// split breaks a cue that does not fit into the fewest parts of similar length that do.
+// Time is shared out in proportion to each part's characters.
+func (l Layout) split(c Cue) []Cue {
+	if l.fits(c.Text, c.Duration()) {
+		return []Cue{c}
+	}
+
+	words := strings.Fields(c.Text)
+	n := 2
+	if l.MaxDuration > 0 {
+		n = max(n, int((c.Duration()+l.MaxDuration-1)/l.MaxDuration))
+	}
+	for ; n < len(words); n++ {
+		parts := splitWords(words, n)
+		if l.partsFit(parts, c) {
+			return timeParts(parts, c)
+		}
+	}
+	// One word per cue is as far as splitting goes
+	return timeParts(splitWords(words, len(words)), c)
+}
+
+func (l Layout) partsFit(parts []string, c Cue) bool {
+	for _, part := range timeParts(parts, c) {
+		if !l.fits(part.Text, part.Duration()) {
+			return false
+		}
+	}
+	return true
+}
+
+// merge folds cues shorter than MergeBelow into the previous cue, or else the next one,
+// when the two are no further apart than MergeBelow and the result still fits
+func (l Layout) merge(cues []Cue) []Cue {
+	if l.MergeBelow <= 0 {
+		return cues
+	}
+
+	canMerge := func(a, b Cue) bool {
+		return b.Start-a.End <= l.MergeBelow && l.fits(a.Text+" "+b.Text, b.End-a.Start)
+	}
+
+	var out []Cue
+	for i := 0; i < len(cues); i++ {
+		c := cues[i]
+		if c.Duration() >= l.MergeBelow {
+			out = append(out, c)
+			continue
+		}
+		if n := len(out); n > 0 && canMerge(out[n-1], c) {
+			out[n-1].Text += " " + c.Text
+			out[n-1].End = c.End
+			continue
+		}
+		if i+1 < len(cues) && canMerge(c, cues[i+1]) {
+			cues[i+1].Text = c.Text + " " + cues[i+1].Text
+			cues[i+1].Start = c.Start
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// retime enforces MinGap between cues and extends cues shorter than MinDuration
+// as far as the next cue and the gap allow
+func (l Layout) retime(cues []Cue) {
+	for i := range cues {
+		c := &cues[i]
+		limit := time.Duration(-1)
+		if i+1 < len(cues) {
+			limit = cues[i+1].Start - l.MinGap
+		}
+
+		if l.MinDuration > 0 && c.Duration() < l.MinDuration {
+			c.End = c.Start + l.MinDuration
+		}
+		// Never overlap or crowd the next cue, but keep cues that start too close to it intact
+		if limit >= 0 && c.End > limit && limit > c.Start {
+			c.End = limit
+		}
+	}
+}
+
+// Wrap breaks text into lines of at most maxChars characters at spaces; words longer
+// than maxChars get a line of their own. With balance, the same number of lines is
+// kept but their lengths are evened out. maxChars <= 0 returns the text as one line.
+func Wrap(text string, maxChars int, balance bool) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if maxChars <= 0 {
+		return []string{strings.Join(words, " ")}
+	}
+
+	lines := wrapWords(words, maxChars)
+	if !balance || len(lines) < 2 {
+		return lines
+	}
+	// The narrowest width that still needs no more lines gives the most even lines
+	lo := max(longestWord(words), (utf8.RuneCountInString(strings.Join(words, " "))+len(lines)-1)/len(lines))
+	for width := lo; width < maxChars; width++ {
+		if balanced := wrapWords(words, width); len(balanced) <= len(lines) {
+			return balanced
+		}
+	}
+	return lines
+}
+
+// wrapWords fills lines greedily up to width characters
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	n := 0
+	for _, w := range words {
+		wn := utf8.RuneCountInString(w)
+		if n > 0 && n+1+wn > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			n = 0
+		}
+		if n > 0 {
+			line.WriteByte(' ')
+			n++
+		}
+		line.WriteString(w)
+		n += wn
+	}
+	return append(lines, line.String())
+}
+
+func longestWord(words []string) int {
+	longest := 0
+	for _, w := range words {
+		longest = max(longest, utf8.RuneCountInString(w))
+	}
+	return longest
+}
+
+// splitWords groups words into n parts of similar character length, preferring to
+// end a part after punctuation within a third of a part's length of the target
+func splitWords(words []string, n int) []string {
+	n = min(n, len(words))
+	total := utf8.RuneCountInString(strings.Join(words, " "))
+	bonus := total / (3 * n)
+
+	parts := make([]string, 0, n)
+	start, done := 0, 0
+	for k := 1; k < n; k++ {
+		target := total * k / n
+		// Leave at least one word for every remaining part
+		end, best := start+1, 0
+		pos := done
+		for i := start; i < len(words)-(n-k); i++ {
+			pos += utf8.RuneCountInString(words[i]) + 1
+			score := abs(pos - target)
+			if endsClause(words[i]) {
+				score -= bonus
+			}
+			if i == start || score < best {
+				end, best = i+1, score
+			}
+		}
+		part := strings.Join(words[start:end], " ")
+		parts = append(parts, part)
+		done += utf8.RuneCountInString(part) + 1
+		start = end
+	}
+	return append(parts, strings.Join(words[start:], " "))
+}
+
+// timeParts spreads c's time over parts in proportion to their characters
+func timeParts(parts []string, c Cue) []Cue {
+	total := 0
+	for _, part := range parts {
+		total += utf8.RuneCountInString(part)
+	}
+
+	out := make([]Cue, len(parts))
+	start, chars := c.Start, 0
+	for i, part := range parts {
+		chars += utf8.RuneCountInString(part)
+		end := c.Start + time.Duration(int64(c.Duration())*int64(chars)/int64(total))
+		if i == len(parts)-1 {
+			end = c.End
+		}
+		out[i] = Cue{Start: start, End: end, Text: part}
+		start = end
+	}
+	return out
+}
+
+// endsClause reports whether word ends a sentence or clause
+func endsClause(word string) bool {
+	r, _ := utf8.DecodeLastRuneInString(word)
+	return strings.ContainsRune(".!?,;:。！？，", r)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
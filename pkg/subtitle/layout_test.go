@@ -0,0 +1,76 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		max     int
+		balance bool
+		want    []string
+	}{
+		{"fits", "Hello world", 42, false, []string{"Hello world"}},
+		{"greedy", "In this lesson we deploy the service to a Kubernetes cluster", 42, false,
+			[]string{"In this lesson we deploy the service to a", "Kubernetes cluster"}},
+		{"balanced", "In this lesson we deploy the service to a Kubernetes cluster", 42, true,
+			[]string{"In this lesson we deploy the", "service to a Kubernetes cluster"}},
+		{"long word", "see https://example.com/a/very/long/path now", 10, false,
+			[]string{"see", "https://example.com/a/very/long/path", "now"}},
+		{"no limit", "a  b\nc", 0, true, []string{"a b c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(tt.text, tt.max, tt.balance)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Wrap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutApply(t *testing.T) {
+	layout := Layout{
+		MaxCharsPerLine: 20,
+		MaxLines:        2,
+		MaxDuration:     6 * time.Second,
+		MinDuration:     time.Second,
+		MinGap:          100 * time.Millisecond,
+		MergeBelow:      500 * time.Millisecond,
+		Balance:         true,
+	}
+	cues := []Cue{
+		// Too long for two lines of 20 characters: split at the sentence end,
+		// time shared by length (30 and 17 characters)
+		{Start: 0, End: 4700 * time.Millisecond, Text: "This sentence is far too long. It needs two cues"},
+		// Tiny cue merged into the previous one
+		{Start: 4700 * time.Millisecond, End: 5 * time.Second, Text: "now."},
+		// Short cue extended to the minimum display time
+		{Start: 5200 * time.Millisecond, End: 5800 * time.Millisecond, Text: "Okay, next"},
+		// Starts 50ms after the extended cue would end, so the gap wins;
+		// longer than 6s, so split by length (5 and 3 characters)
+		{Start: 5950 * time.Millisecond, End: 12350 * time.Millisecond, Text: "Slide two"},
+	}
+
+	got := layout.Apply(cues)
+	want := []Cue{
+		{Index: 1, Start: 0, End: 2900 * time.Millisecond, Text: "This sentence is\nfar too long."},
+		{Index: 2, Start: 3 * time.Second, End: 5 * time.Second, Text: "It needs two\ncues now."},
+		{Index: 3, Start: 5200 * time.Millisecond, End: 5850 * time.Millisecond, Text: "Okay, next"},
+		{Index: 4, Start: 5950 * time.Millisecond, End: 9850 * time.Millisecond, Text: "Slide"},
+		{Index: 5, Start: 9950 * time.Millisecond, End: 12350 * time.Millisecond, Text: "two"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Apply() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("cue %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
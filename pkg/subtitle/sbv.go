@@ -0,0 +1,25 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FormatSBV serializes cues as YouTube SubViewer (H:MM:SS.mmm,H:MM:SS.mmm timing lines)
+func FormatSBV(cues []Cue) string {
+	var sb strings.Builder
+	for _, c := range cues {
+		fmt.Fprintf(&sb, "%s,%s\n%s\n\n", formatSBVTimestamp(c.Start), formatSBVTimestamp(c.End), strings.Join(c.Lines(), "\n"))
+	}
+	return sb.String()
+}
+
+// formatSBVTimestamp renders d as H:MM:SS.mmm (hours are not zero-padded)
+func formatSBVTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
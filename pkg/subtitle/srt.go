@@ -0,0 +1,145 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// reTiming matches the "start --> end" line, ignoring any position settings after the end time
+var reTiming = regexp.MustCompile(`^\s*([\d:.,]+)\s*-{1,2}>\s*([\d:.,]+)`)
+
+// ParseSRT reads SRT content into cues.
+// It tolerates a UTF-8 BOM, CRLF line endings, missing or broken index lines,
+// sloppy timestamps and multi-line cue text. Blocks whose timing line cannot be
+// parsed are skipped; blocks without a timing line are treated as the continuation
+// of the previous cue's text (i.e. a blank line inside the cue).
+func ParseSRT(r io.Reader) ([]Cue, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read srt: %w", err)
+	}
+
+	content := string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	var cues []Cue
+	for _, block := range splitBlocks(content) {
+		timingAt := -1
+		if strings.Contains(block[0], "-->") {
+			timingAt = 0
+		} else if len(block) > 1 && strings.Contains(block[1], "-->") {
+			timingAt = 1
+		}
+
+		if timingAt < 0 {
+			if len(cues) > 0 {
+				last := &cues[len(cues)-1]
+				last.Text += "\n\n" + strings.Join(block, "\n")
+			}
+			continue
+		}
+
+		m := reTiming.FindStringSubmatch(block[timingAt])
+		if m == nil {
+			continue
+		}
+		start, err := parseTimestamp(m[1])
+		if err != nil {
+			continue
+		}
+		end, err := parseTimestamp(m[2])
+		if err != nil {
+			continue
+		}
+		if end < start {
+			end = start
+		}
+
+		index := 0
+		if timingAt == 1 {
+			index, _ = strconv.Atoi(strings.TrimSpace(block[0]))
+		}
+		if index <= 0 {
+			index = 1
+			if len(cues) > 0 {
+				index = cues[len(cues)-1].Index + 1
+			}
+		}
+
+		cues = append(cues, Cue{
+			Index: index,
+			Start: start,
+			End:   end,
+			Text:  strings.Join(block[timingAt+1:], "\n"),
+		})
+	}
+
+	return cues, nil
+}
+
+// ReadSRTFile parses the SRT file at path
+func ReadSRTFile(path string) ([]Cue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open srt: %w", err)
+	}
+	defer f.Close()
+
+	return ParseSRT(f)
+}
+
+// FormatSRT serializes cues in the exact layout whisper.cpp writes,
+// so parsing and formatting its output round-trips byte for byte
+func FormatSRT(cues []Cue) string {
+	var sb strings.Builder
+	for i, c := range cues {
+		index := c.Index
+		if index <= 0 {
+			index = i + 1
+		}
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", index, formatTimestamp(c.Start, ","), formatTimestamp(c.End, ","), c.Text)
+	}
+	return sb.String()
+}
+
+// WriteSRT writes cues to w in SRT format
+func WriteSRT(w io.Writer, cues []Cue) error {
+	if _, err := io.WriteString(w, FormatSRT(cues)); err != nil {
+		return fmt.Errorf("write srt: %w", err)
+	}
+	return nil
+}
+
+// WriteSRTFile writes cues to path in SRT format
+func WriteSRTFile(path string, cues []Cue) error {
+	if err := os.WriteFile(path, []byte(FormatSRT(cues)), 0644); err != nil {
+		return fmt.Errorf("write srt: %w", err)
+	}
+	return nil
+}
+
+// splitBlocks splits normalized SRT content on blank lines
+func splitBlocks(content string) [][]string {
+	var blocks [][]string
+	var current []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
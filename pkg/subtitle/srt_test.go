@@ -0,0 +1,115 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// whisperSRT mirrors whisper.cpp -osrt output, including the leading space on each text line
+const whisperSRT = `1
+00:00:00,000 --> 00:00:04,520
+ Welcome to the Global Goal Tool.
+
+2
+00:00:04,520 --> 00:00:09,000
+ First, open the login page.
+
+3
+01:02:03,004 --> 01:02:05,250
+ Click "Sign in".
+
+`
+
+func TestParseSRTRoundTrip(t *testing.T) {
+	cues, err := ParseSRT(strings.NewReader(whisperSRT))
+	if err != nil {
+		t.Fatalf("ParseSRT() error = %v", err)
+	}
+	if len(cues) != 3 {
+		t.Fatalf("len(cues) = %d, want 3", len(cues))
+	}
+
+	want := Cue{Index: 3, Start: time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond, End: time.Hour + 2*time.Minute + 5250*time.Millisecond, Text: ` Click "Sign in".`}
+	if cues[2] != want {
+		t.Errorf("cues[2] = %+v, want %+v", cues[2], want)
+	}
+
+	if got := FormatSRT(cues); got != whisperSRT {
+		t.Errorf("FormatSRT() did not round-trip:\n%q\nwant\n%q", got, whisperSRT)
+	}
+}
+
+func TestParseSRTTolerance(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Cue
+	}{
+		{
+			name:  "BOM and CRLF",
+			input: "\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,000\r\nHello\r\n\r\n",
+			want:  []Cue{{Index: 1, Start: time.Second, End: 2 * time.Second, Text: "Hello"}},
+		},
+		{
+			name:  "multi-line cue",
+			input: "1\n00:00:01,000 --> 00:00:02,000\nline one\nline two\n",
+			want:  []Cue{{Index: 1, Start: time.Second, End: 2 * time.Second, Text: "line one\nline two"}},
+		},
+		{
+			name:  "sloppy timestamps",
+			input: "1\n0:1.5 --> 00:00:02.25 X1:10 X2:20\nHello\n",
+			want:  []Cue{{Index: 1, Start: 1500 * time.Millisecond, End: 2250 * time.Millisecond, Text: "Hello"}},
+		},
+		{
+			name:  "missing index",
+			input: "7\n00:00:01,000 --> 00:00:02,000\nA\n\n00:00:03,000 --> 00:00:04,000\nB\n",
+			want: []Cue{
+				{Index: 7, Start: time.Second, End: 2 * time.Second, Text: "A"},
+				{Index: 8, Start: 3 * time.Second, End: 4 * time.Second, Text: "B"},
+			},
+		},
+		{
+			name:  "malformed timestamp skipped",
+			input: "1\n00:00:01,000 --> garbage\nA\n\n2\n00:00:03,000 --> 00:00:04,000\nB\n",
+			want:  []Cue{{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Text: "B"}},
+		},
+		{
+			name:  "blank line inside cue",
+			input: "1\n00:00:01,000 --> 00:00:02,000\nA\n\nstill A\n\n2\n00:00:03,000 --> 00:00:04,000\nB\n",
+			want: []Cue{
+				{Index: 1, Start: time.Second, End: 2 * time.Second, Text: "A\n\nstill A"},
+				{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Text: "B"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues, err := ParseSRT(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseSRT() error = %v", err)
+			}
+			if len(cues) != len(tt.want) {
+				t.Fatalf("ParseSRT() = %+v, want %+v", cues, tt.want)
+			}
+			for i := range cues {
+				if cues[i] != tt.want[i] {
+					t.Errorf("cues[%d] = %+v, want %+v", i, cues[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	cues, err := ParseSRT(strings.NewReader(whisperSRT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Welcome to the Global Goal Tool.\nFirst, open the login page.\nClick \"Sign in\".\n"
+	if got := PlainText(cues); got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package subtitle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ASS border styles
+const (
+	BorderOutline   = 1 // outline + drop shadow around the glyphs
+	BorderOpaqueBox = 3 // opaque box behind each line, drawn in OutlineColour
+)
+
+// Style is an ASS [V4+ Styles] entry. Colours use the ASS &HAABBGGRR notation.
+type Style struct {
+	FontName      string
+	FontSize      float64
+	PrimaryColour string
+	OutlineColour string
+	BackColour    string
+	Bold          bool
+	BorderStyle   int
+	Outline       float64
+	Shadow        float64
+	// Alignment is the numpad position: 1-3 bottom, 4-6 middle, 7-9 top
+	Alignment int
+	MarginL   int
+	MarginR   int
+	MarginV   int
+}
+
+// DefaultStyle returns the libass defaults used by `ffmpeg -i x.srt x.ass`
+func DefaultStyle() Style {
+	return Style{
+		FontName:      "Arial",
+		FontSize:      16,
+		PrimaryColour: "&Hffffff",
+		OutlineColour: "&H0",
+		BackColour:    "&H0",
+		BorderStyle:   BorderOutline,
+		Outline:       1,
+		Alignment:     2,
+		MarginL:       10,
+		MarginR:       10,
+		MarginV:       10,
+	}
+}
+
+// line renders s as a "Style:" line named Default
+func (s Style) line() string {
+	bold := 0
+	if s.Bold {
+		bold = -1
+	}
+	return fmt.Sprintf("Style: Default,%s,%g,%s,%s,%s,%s,%d,0,0,0,100,100,0,0,%d,%g,%g,%d,%d,%d,%d,0",
+		s.FontName, s.FontSize, s.PrimaryColour, s.PrimaryColour, s.OutlineColour, s.BackColour,
+		bold, s.BorderStyle, s.Outline, s.Shadow, s.Alignment, s.MarginL, s.MarginR, s.MarginV)
+}
+
+// ParseColor converts "#RRGGBB" or "#RRGGBBAA" (AA = opacity, ff is opaque) to ASS &HAABBGGRR.
+// Values already in &H notation are returned unchanged.
+func ParseColor(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToUpper(s), "&H") {
+		if _, err := strconv.ParseUint(strings.TrimSuffix(s[2:], "&"), 16, 32); err != nil {
+			return "", fmt.Errorf("invalid ASS colour %q", s)
+		}
+		return s, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return "", fmt.Errorf("invalid colour %q (want #RRGGBB or #RRGGBBAA)", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return "", fmt.Errorf("invalid colour %q (want #RRGGBB or #RRGGBBAA)", s)
+	}
+	opacity := uint64(0xff)
+	if len(hex) == 8 {
+		opacity = v & 0xff
+		v >>= 8
+	}
+	r, g, b := v>>16&0xff, v>>8&0xff, v&0xff
+	return fmt.Sprintf("&H%02X%02X%02X%02X", 0xff-opacity, b, g, r), nil
+}
+
+var alignments = map[string]int{
+	"bottom-left": 1, "bottom": 2, "bottom-center": 2, "bottom-right": 3,
+	"middle-left": 4, "middle": 5, "center": 5, "middle-right": 6,
+	"top-left": 7, "top": 8, "top-center": 8, "top-right": 9,
+}
+
+// ParseAlignment accepts a numpad position (1-9) or a name such as "bottom" or "top-right"
+func ParseAlignment(s string) (int, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if n, ok := alignments[name]; ok {
+		return n, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= 1 && n <= 9 {
+		return n, nil
+	}
+	return 0, fmt.Errorf("invalid alignment %q (1-9, bottom, top-right, ...)", s)
+}
@@ -0,0 +1,56 @@
+package subtitle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reTimestamp accepts HH:MM:SS,mmm plus the usual deviations: missing hours,
+// single-digit fields, '.' or ':' as the millisecond separator and short fractions
+var reTimestamp = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{1,2})(?:[,.:](\d{1,3})\d*)?$`)
+
+// parseTimestamp converts an SRT-style timestamp into a duration
+func parseTimestamp(s string) (time.Duration, error) {
+	m := reTimestamp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	var hours, minutes, seconds, millis int
+	if m[1] != "" {
+		hours, _ = strconv.Atoi(m[1])
+	}
+	minutes, _ = strconv.Atoi(m[2])
+	seconds, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		// The fraction is a decimal, so "5" means 500 ms rather than 5 ms
+		frac := m[4] + strings.Repeat("0", 3-len(m[4]))
+		millis, _ = strconv.Atoi(frac)
+	}
+
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timestamp %q: field out of range", s)
+	}
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(millis)*time.Millisecond, nil
+}
+
+// FormatTimestamp renders d as an SRT timestamp, HH:MM:SS,mmm
+func FormatTimestamp(d time.Duration) string {
+	return formatTimestamp(d, ",")
+}
+
+// formatTimestamp renders d as HH:MM:SS<sep>mmm
+func formatTimestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
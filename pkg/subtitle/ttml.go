@@ -0,0 +1,33 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// FormatTTML serializes cues as a minimal TTML document (HH:MM:SS.mmm clock times)
+func FormatTTML(cues []Cue) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<tt xmlns="http://www.w3.org/ns/ttml" xml:lang="">` + "\n")
+	sb.WriteString("  <body>\n    <div>\n")
+	for _, c := range cues {
+		lines := c.Lines()
+		escaped := make([]string, len(lines))
+		for i, line := range lines {
+			escaped[i] = xmlEscape(line)
+		}
+		fmt.Fprintf(&sb, "      <p begin=\"%s\" end=\"%s\">%s</p>\n", formatTimestamp(c.Start, "."), formatTimestamp(c.End, "."),
+			strings.Join(escaped, "<br/>"))
+	}
+	sb.WriteString("    </div>\n  </body>\n</tt>\n")
+	return sb.String()
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
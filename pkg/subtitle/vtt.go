@@ -0,0 +1,23 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+)
+
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// FormatVTT serializes cues as WebVTT (HH:MM:SS.mmm timestamps, text entities escaped)
+func FormatVTT(cues []Cue) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for i, c := range cues {
+		index := c.Index
+		if index <= 0 {
+			index = i + 1
+		}
+		fmt.Fprintf(&sb, "%d\n%s --> %s\n%s\n\n", index, formatTimestamp(c.Start, "."), formatTimestamp(c.End, "."),
+			vttEscaper.Replace(strings.Join(c.Lines(), "\n")))
+	}
+	return sb.String()
+}